@@ -0,0 +1,118 @@
+package psolver
+
+import (
+	"bytes"
+	"context"
+	"slices"
+)
+
+// Placement is a piece placed on the board.
+type Placement struct {
+	Name  byte
+	State int
+	// Cells are the cells the piece covers, in scan order.
+	Cells []Point
+}
+
+// Analysis is the result of Analyze, the pieces that can cover each cell and
+// the placements that are in every solution.
+type Analysis struct {
+	Width, Height int
+	Solutions     int
+	// Forced are the placements found in every solution, sorted by name.
+	Forced []Placement
+
+	cells    [][]byte
+	counts   map[string]int
+	smallest *Matrix
+}
+
+// Analyze enumerates all the solutions of the board. When ctx is cancelled it
+// returns the analysis of the solutions found so far with the context error.
+// Limit in opts is ignored.
+func Analyze(ctx context.Context, m *Matrix, pieces []Piece, opts SolveOptions) (*Analysis, error) {
+	if err := opts.Engine.Check(m); err != nil {
+		return nil, err
+	}
+	opts.Limit = 0
+	a := &Analysis{
+		Width:  m.Width,
+		Height: m.Height,
+		cells:  make([][]byte, len(m.data)),
+		counts: map[string]int{},
+	}
+
+	var first []Placement
+	for sol := range SolveContext(ctx, m, pieces, opts) {
+		a.Solutions++
+		pls := placementsOf(m, sol)
+		for _, pl := range pls {
+			a.counts[a.key(pl)]++
+			for _, c := range pl.Cells {
+				i := c.Y*m.Width + c.X
+				if !slices.Contains(a.cells[i], pl.Name) {
+					a.cells[i] = append(a.cells[i], pl.Name)
+				}
+			}
+		}
+		if a.smallest == nil || bytes.Compare(sol.data, a.smallest.data) < 0 {
+			a.smallest = sol
+			first = pls
+		}
+	}
+
+	for i := range a.cells {
+		slices.Sort(a.cells[i])
+	}
+	for _, pl := range first {
+		if a.counts[a.key(pl)] == a.Solutions {
+			a.Forced = append(a.Forced, pl)
+		}
+	}
+	return a, ctx.Err()
+}
+
+// placementsOf returns the placements of the pieces added to the board m in
+// the solution, sorted by name.
+func placementsOf(m, sol *Matrix) []Placement {
+	var res []Placement
+	for i, c := range sol.data {
+		if c == m.data[i] {
+			continue
+		}
+		idx := slices.IndexFunc(res, func(pl Placement) bool {
+			return pl.Name == c && sol.samePlacement(pl.Cells[0].Y*m.Width+pl.Cells[0].X, i)
+		})
+		if idx < 0 {
+			res = append(res, Placement{Name: c, State: sol.pieces[c]})
+			idx = len(res) - 1
+		}
+		res[idx].Cells = append(res[idx].Cells, Point{X: i % m.Width, Y: i / m.Width})
+	}
+	slices.SortFunc(res, func(a, b Placement) int { return int(a.Name) - int(b.Name) })
+	return res
+}
+
+func (a *Analysis) key(pl Placement) string {
+	cells := make([]int, 0, len(pl.Cells))
+	for _, c := range pl.Cells {
+		cells = append(cells, c.Y*a.Width+c.X)
+	}
+	slices.Sort(cells)
+	return string(pl.Name) + string(intsKey(cells))
+}
+
+// Cell returns the names of the pieces that cover the cell in some solution,
+// sorted. It is empty for the blocked cells and the cells out of the board.
+func (a *Analysis) Cell(p Point) []byte {
+	if p.X < 0 || p.X >= a.Width || p.Y < 0 || p.Y >= a.Height {
+		return nil
+	}
+	return a.cells[p.Y*a.Width+p.X]
+}
+
+// Count returns the number of solutions with the placement, the state is
+// ignored.
+func (a *Analysis) Count(pl Placement) int {
+	return a.counts[a.key(pl)]
+}
@@ -0,0 +1,59 @@
+package psolver
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestAnalyze(t *testing.T) {
+	a, err := Analyze(context.Background(), NewMatrix(5, 3), smallSet(t, PieceP, PieceU, PieceV), SolveOptions{Unique: true})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if a.Solutions != 1 {
+		t.Fatalf("Expected 1 solution, got %d", a.Solutions)
+	}
+	if len(a.Forced) != 3 {
+		t.Errorf("Expected 3 forced placements, got %d", len(a.Forced))
+	}
+	for _, pl := range a.Forced {
+		if len(pl.Cells) != 5 {
+			t.Errorf("Expected 5 cells for %s, got %d", string(pl.Name), len(pl.Cells))
+		}
+		for _, c := range pl.Cells {
+			if !slices.Equal(a.Cell(c), []byte{pl.Name}) {
+				t.Errorf("Expected only %s at %v, got %q", string(pl.Name), c, a.Cell(c))
+			}
+		}
+	}
+}
+
+func TestAnalyzeCalendar(t *testing.T) {
+	cal := NewPersianCalendar()
+	if err := cal.SetDate(1, 1, 1, 1404); err != nil {
+		t.Fatalf("SetDate failed: %v", err)
+	}
+
+	a, err := Analyze(context.Background(), &cal.Matrix, New12(), SolveOptions{Engine: EngineAuto})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if a.Solutions != 1210 {
+		t.Errorf("Expected 1210 solutions, got %d", a.Solutions)
+	}
+	for _, pl := range a.Forced {
+		if n := a.Count(pl); n != a.Solutions {
+			t.Errorf("Forced %s is in %d solutions", string(pl.Name), n)
+		}
+	}
+	for i, c := range cal.data {
+		cell := a.Cell(Point{X: i % cal.Width, Y: i / cal.Width})
+		if c != 0 && len(cell) != 0 {
+			t.Errorf("Expected no piece on the blocked cell %d, got %q", i, cell)
+		}
+		if c == 0 && len(cell) == 0 {
+			t.Errorf("Expected some piece on the cell %d", i)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package psolver
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	icolor "image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"io"
+	"slices"
+)
+
+// AnimationExporter exports the solving of a board as an animated GIF or
+// APNG, one frame for each step, drawn by a PNGExporter.
+type AnimationExporter struct {
+	// PNG draws the frames, NewPNGExporter when nil.
+	PNG *PNGExporter
+	// APNG writes an animated PNG with the exact colors instead of a GIF.
+	APNG bool
+	// Delay of the frames and LastDelay of the last one, the solution, in
+	// hundredths of a second.
+	Delay, LastDelay int
+}
+
+// NewAnimationExporter creates a new AnimationExporter with default settings
+func NewAnimationExporter() *AnimationExporter {
+	return &AnimationExporter{
+		PNG:       NewPNGExporter(),
+		Delay:     50,
+		LastDelay: 300,
+	}
+}
+
+// Export writes the pieces of the solution m placed one by one, in the order
+// of SolutionSteps, on the board with only the blocked cells of m.
+func (a *AnimationExporter) Export(m *Matrix, w io.Writer) error {
+	start := m.duplicate()
+	start.Footer = m.Footer
+	for i, c := range start.data {
+		if c != 'O' {
+			start.data[i] = 0
+			start.own(i, -1)
+		}
+	}
+	clear(start.pieces)
+	return a.ExportSteps(start, SolutionSteps(start, m), w)
+}
+
+// ExportSteps writes the board m and the board after each of the steps, like
+// the steps of Trace.
+func (a *AnimationExporter) ExportSteps(m *Matrix, steps []Step, w io.Writer) error {
+	p := a.PNG
+	if p == nil {
+		p = NewPNGExporter()
+	}
+	theme := themeOrDefault(p.Theme)
+
+	// The colors of all the pieces of the steps, so the fallback colors stay
+	// the same in all the frames.
+	all := NewMatrix(len(m.data)+len(steps), 1)
+	copy(all.data, m.data)
+	for i, st := range steps {
+		all.data[len(m.data)+i] = st.Name
+	}
+	colors := theme.colors(all)
+
+	board := m.duplicate()
+	board.Footer = m.Footer
+	frame := func(i int) (*image.RGBA, error) {
+		if i > 0 {
+			steps[i-1].apply(board)
+		}
+		return p.render(board, theme, colors)
+	}
+	delay := func(i int) int {
+		if i == len(steps) {
+			return a.LastDelay
+		}
+		return a.Delay
+	}
+
+	if a.APNG {
+		return writeAPNG(w, len(steps)+1, frame, delay)
+	}
+	return writeGIF(w, len(steps)+1, frame, delay, gifPalette(theme, colors))
+}
+
+// gifPalette returns the colors of the theme and of the pieces, with the
+// transparent color first, and fills the rest with the Plan 9 colors for the
+// smooth edges and the text.
+func gifPalette(theme *Theme, colors map[byte]Color) icolor.Palette {
+	pal := icolor.Palette{icolor.Transparent}
+	add := func(c Color) {
+		if c.A != 0 && !slices.Contains(pal, icolor.Color(c)) {
+			pal = append(pal, c)
+		}
+	}
+	for _, c := range []Color{theme.Background, theme.cell(), theme.Border, theme.Text, theme.Text.faded(), theme.Blocked} {
+		add(c)
+	}
+	keys := make([]byte, 0, len(colors))
+	for k := range colors {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		add(colors[k])
+	}
+	for _, c := range palette.Plan9 {
+		if len(pal) == 256 {
+			break
+		}
+		pal = append(pal, c)
+	}
+	return pal[:min(len(pal), 256)]
+}
+
+// writeGIF writes the frames as an animated GIF.
+func writeGIF(w io.Writer, n int, frame func(int) (*image.RGBA, error), delay func(int) int, pal icolor.Palette) error {
+	g := &gif.GIF{}
+	for i := range n {
+		img, err := frame(i)
+		if err != nil {
+			return err
+		}
+		pm := image.NewPaletted(img.Bounds(), pal)
+		draw.Draw(pm, pm.Bounds(), img, image.Point{}, draw.Src)
+		g.Image = append(g.Image, pm)
+		g.Delay = append(g.Delay, delay(i))
+		// The transparent cells of the frame clear the previous one.
+		g.Disposal = append(g.Disposal, gif.DisposalBackground)
+	}
+	return gif.EncodeAll(w, g)
+}
+
+// translucent is an image encoded by image/png with the alpha channel even
+// when it is opaque, so all the frames of an APNG have the same format.
+type translucent struct {
+	*image.RGBA
+}
+
+func (translucent) Opaque() bool {
+	return false
+}
+
+// writeAPNG writes the frames as an animated PNG, the first frame is also the
+// image shown by the viewers without the support of APNG.
+func writeAPNG(w io.Writer, n int, frame func(int) (*image.RGBA, error), delay func(int) int) error {
+	if _, err := io.WriteString(w, "\x89PNG\r\n\x1a\n"); err != nil {
+		return err
+	}
+
+	seq := uint32(0)
+	var ihdr []byte
+	for i := range n {
+		img, err := frame(i)
+		if err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, translucent{img}); err != nil {
+			return err
+		}
+		chunks, err := pngChunks(buf.Bytes())
+		if err != nil {
+			return err
+		}
+
+		for _, c := range chunks {
+			switch c.typ {
+			case "IHDR":
+				if i == 0 {
+					ihdr = c.data
+					if err := writeChunk(w, "IHDR", c.data); err != nil {
+						return err
+					}
+					// The number of the frames, and of the plays, zero for ever.
+					actl := binary.BigEndian.AppendUint32(nil, uint32(n))
+					if err := writeChunk(w, "acTL", binary.BigEndian.AppendUint32(actl, 0)); err != nil {
+						return err
+					}
+				} else if !bytes.Equal(c.data, ihdr) {
+					return errors.New("the frames have different sizes")
+				}
+
+				b := img.Bounds()
+				fctl := binary.BigEndian.AppendUint32(nil, seq)
+				fctl = binary.BigEndian.AppendUint32(fctl, uint32(b.Dx()))
+				fctl = binary.BigEndian.AppendUint32(fctl, uint32(b.Dy()))
+				fctl = append(fctl, make([]byte, 8)...) // the offsets
+				fctl = binary.BigEndian.AppendUint16(fctl, uint16(delay(i)))
+				fctl = binary.BigEndian.AppendUint16(fctl, 100)
+				// No dispose, and the frame replaces the previous one.
+				fctl = append(fctl, 0, 0)
+				seq++
+				if err := writeChunk(w, "fcTL", fctl); err != nil {
+					return err
+				}
+			case "IDAT":
+				if i == 0 {
+					err = writeChunk(w, "IDAT", c.data)
+				} else {
+					err = writeChunk(w, "fdAT", append(binary.BigEndian.AppendUint32(nil, seq), c.data...))
+					seq++
+				}
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return writeChunk(w, "IEND", nil)
+}
+
+type pngChunk struct {
+	typ  string
+	data []byte
+}
+
+// pngChunks splits an encoded PNG into its chunks.
+func pngChunks(b []byte) ([]pngChunk, error) {
+	b = b[8:]
+	var res []pngChunk
+	for len(b) >= 12 {
+		n := binary.BigEndian.Uint32(b)
+		if int(n) > len(b)-12 {
+			return nil, errors.New("invalid PNG chunk")
+		}
+		res = append(res, pngChunk{typ: string(b[4:8]), data: b[8 : 8+n]})
+		b = b[12+n:]
+	}
+	return res, nil
+}
+
+// writeChunk writes a PNG chunk with its length and checksum.
+func writeChunk(w io.Writer, typ string, data []byte) error {
+	buf := binary.BigEndian.AppendUint32(nil, uint32(len(data)))
+	buf = append(buf, typ...)
+	buf = append(buf, data...)
+	buf = binary.BigEndian.AppendUint32(buf, crc32.ChecksumIEEE(buf[4:]))
+	_, err := w.Write(buf)
+	return err
+}
@@ -0,0 +1,122 @@
+package psolver
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// BagItem is a kind of piece in a Bag.
+type BagItem struct {
+	Piece Piece
+	// Count is the number of copies of the piece, zero means one. The copies
+	// share the name of the piece, so the matrix of a solution shows them with
+	// the same letter and keeps the state of only one of them.
+	Count int
+	// Optional copies may be left unused, otherwise all of them are placed.
+	Optional bool
+}
+
+// Bag is a set of pieces with multiple or optional copies. Each piece should
+// be listed once, with the number of its copies in Count.
+type Bag []BagItem
+
+// NewBag returns the bag with exactly one copy of each piece.
+func NewBag(pieces ...Piece) Bag {
+	b := make(Bag, 0, len(pieces))
+	for _, p := range pieces {
+		b = append(b, BagItem{Piece: p, Count: 1})
+	}
+	return b
+}
+
+// bounds returns the pieces of the bag, with the least and the most copies of
+// each one that a solution uses.
+func (b Bag) bounds() ([]Piece, []int, []int) {
+	pieces := make([]Piece, 0, len(b))
+	least := make([]int, 0, len(b))
+	most := make([]int, 0, len(b))
+	for _, item := range b {
+		n := max(item.Count, 1)
+		pieces = append(pieces, item.Piece)
+		most = append(most, n)
+		if item.Optional {
+			least = append(least, 0)
+		} else {
+			least = append(least, n)
+		}
+	}
+	return pieces, least, most
+}
+
+// Area returns the least and the most cells the pieces of the bag cover.
+func (b Bag) Area() (int, int) {
+	pieces, least, most := b.bounds()
+	var lo, hi int
+	for i, p := range pieces {
+		points, err := p.Position(Point{}, 0)
+		if err != nil {
+			continue
+		}
+		lo += least[i] * len(points)
+		hi += most[i] * len(points)
+	}
+	return lo, hi
+}
+
+// ParseBag reads a bag from a comma separated list of piece names, the upper
+// case pentominoes and the lower case tetrominoes. A name may be followed by
+// "*" and the number of its copies, and by "?" when the copies are optional:
+//
+//	F,I,L,N,P,T,U,V,W,X,Y,Z,o?
+//	L*2,I*3
+func ParseBag(spec string) (Bag, error) {
+	known := map[byte]Piece{}
+	for _, p := range append(New12(), NewTetrominoes()...) {
+		known[p.Name()] = p
+	}
+
+	var b Bag
+	seen := map[byte]struct{}{}
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		bi := BagItem{Count: 1}
+		if strings.HasSuffix(item, "?") {
+			bi.Optional = true
+			item = strings.TrimSuffix(item, "?")
+		}
+		if name, count, ok := strings.Cut(item, "*"); ok {
+			n, err := strconv.Atoi(count)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("%q is invalid count", count)
+			}
+			bi.Count = n
+			item = name
+		}
+
+		if len(item) != 1 {
+			return nil, fmt.Errorf("%q is invalid name", item)
+		}
+		p, ok := known[item[0]]
+		if !ok {
+			return nil, fmt.Errorf("%q is invalid name", item)
+		}
+		// The copies of a piece share its name, see BagItem.
+		if _, ok := seen[item[0]]; ok {
+			return nil, fmt.Errorf("%q is listed twice", item)
+		}
+		seen[item[0]] = struct{}{}
+		bi.Piece = p
+		b = append(b, bi)
+	}
+	return b, nil
+}
+
+// SolveBag is SolveContext for a bag of pieces, the board is solved when all
+// of its cells are filled and all the copies that are not optional are placed.
+func SolveBag(ctx context.Context, m *Matrix, bag Bag, opts SolveOptions) <-chan *Matrix {
+	ans := make(chan *Matrix, opts.Buffer)
+	solve(ctx, m, bag, opts, ans)
+	return ans
+}
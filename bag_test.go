@@ -0,0 +1,101 @@
+package psolver
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSolveBag(t *testing.T) {
+	i := smallSet(t, PieceI)[0]
+	l := smallSet(t, PieceL)[0]
+	square := NewMatrix(8, 8)
+	for _, c := range []int{27, 28, 35, 36} {
+		square.data[c] = 'O'
+	}
+	withSquare := append(NewBag(New12()...), BagItem{Piece: NewTetrominoes()[1], Optional: true})
+
+	tests := []struct {
+		name    string
+		m       *Matrix
+		bag     Bag
+		engines []Engine
+		unique  bool
+		want    int
+	}{
+		{
+			name:    "three I",
+			m:       NewMatrix(5, 3),
+			bag:     Bag{{Piece: i, Count: 3}},
+			engines: []Engine{EngineBacktrack, EngineDLX, EngineBitboard},
+			want:    1,
+		},
+		{
+			name:    "two L",
+			m:       NewMatrix(5, 2),
+			bag:     Bag{{Piece: l, Count: 2}},
+			engines: []Engine{EngineBacktrack, EngineDLX, EngineBitboard},
+			want:    2,
+		},
+		{
+			name:    "optional I",
+			m:       NewMatrix(5, 2),
+			bag:     Bag{{Piece: l, Count: 2, Optional: true}, {Piece: i, Count: 2, Optional: true}},
+			engines: []Engine{EngineBacktrack, EngineDLX, EngineBitboard},
+			want:    3,
+		},
+		{
+			name:    "too few",
+			m:       NewMatrix(5, 3),
+			bag:     Bag{{Piece: i, Count: 2}},
+			engines: []Engine{EngineBacktrack, EngineDLX, EngineBitboard},
+			want:    0,
+		},
+		{
+			name:    "unused square",
+			m:       square,
+			bag:     withSquare,
+			engines: []Engine{EngineBitboard},
+			unique:  true,
+			want:    65,
+		},
+	}
+
+	for _, tt := range tests {
+		for _, e := range tt.engines {
+			t.Run(tt.name+"/"+e.String(), func(t *testing.T) {
+				n := 0
+				for range SolveBag(context.Background(), tt.m, tt.bag, SolveOptions{Engine: e, Unique: tt.unique}) {
+					n++
+				}
+				if n != tt.want {
+					t.Errorf("Expected %d solutions, got %d", tt.want, n)
+				}
+			})
+		}
+	}
+}
+
+func TestParseBag(t *testing.T) {
+	b, err := ParseBag("L*2, I*3?,o")
+	if err != nil {
+		t.Fatalf("ParseBag failed: %v", err)
+	}
+	if len(b) != 3 {
+		t.Fatalf("Expected 3 items, got %d", len(b))
+	}
+	if b[0].Piece.Name() != 'L' || b[0].Count != 2 || b[0].Optional {
+		t.Errorf("Unexpected first item %+v", b[0])
+	}
+	if b[1].Piece.Name() != 'I' || b[1].Count != 3 || !b[1].Optional {
+		t.Errorf("Unexpected second item %+v", b[1])
+	}
+	if lo, hi := b.Area(); lo != 14 || hi != 29 {
+		t.Errorf("Expected area 14 to 29, got %d to %d", lo, hi)
+	}
+
+	for _, spec := range []string{"", "Q", "L*0", "L*x", "LL", "L,L*2"} {
+		if _, err := ParseBag(spec); err == nil {
+			t.Errorf("Expected error for %q", spec)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package psolver
+
+import (
+	"errors"
+	"math/bits"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// bitboardCells is the largest board the bitboard engine can handle.
+const bitboardCells = 128
+
+// ErrBoardTooLarge is returned when EngineBitboard is asked to search a board
+// with more than 128 cells.
+var ErrBoardTooLarge = errors.New("the board is too large for the bitboard engine")
+
+// bitboard is a set of board cells, one bit per index of Matrix.data.
+type bitboard [2]uint64
+
+func (b *bitboard) set(i int) {
+	b[i>>6] |= 1 << (i & 63)
+}
+
+func (b bitboard) overlaps(o bitboard) bool {
+	return b[0]&o[0] != 0 || b[1]&o[1] != 0
+}
+
+func (b bitboard) or(o bitboard) bitboard {
+	return bitboard{b[0] | o[0], b[1] | o[1]}
+}
+
+// firstEmpty returns the lowest cell not in the set.
+func (b bitboard) firstEmpty() int {
+	if b[0] != ^uint64(0) {
+		return bits.TrailingZeros64(^b[0])
+	}
+	return 64 + bits.TrailingZeros64(^b[1])
+}
+
+// bitMove is a placement mask with the index of its placement.
+type bitMove struct {
+	mask bitboard
+	pl   int
+}
+
+// bitSolver is the backtracking search of the first empty cell, using
+// precomputed placement masks indexed by their first cell. The bits are
+// ordered along the shorter side of the board, it keeps the frontier short
+// and prunes the search much earlier than the scan order.
+type bitSolver struct {
+	m      *Matrix
+	pieces []Piece
+	pls    []placement
+	bit    []int         // cell -> bit
+	anchor [][][]bitMove // bit, piece -> placements starting on that bit
+	next   []bitboard    // neighbours of each bit with a higher bit
+	full   bitboard
+	single bool // some piece fits in a single cell
+}
+
+func newBitSolver(m *Matrix, pieces []Piece, pls []placement) *bitSolver {
+	b := &bitSolver{
+		m:      m,
+		pieces: pieces,
+		pls:    pls,
+		bit:    make([]int, len(m.data)),
+		anchor: make([][][]bitMove, len(m.data)),
+		next:   make([]bitboard, len(m.data)),
+	}
+	for i := range b.anchor {
+		b.anchor[i] = make([][]bitMove, len(pieces))
+	}
+	for i := range m.data {
+		x, y := i%m.Width, i/m.Width
+		b.bit[i] = i
+		if m.Height < m.Width {
+			b.bit[i] = x*m.Height + y
+		}
+		b.full.set(i)
+	}
+	for i := range m.data {
+		x, y := i%m.Width, i/m.Width
+		for _, n := range [][2]int{{x - 1, y}, {x + 1, y}, {x, y - 1}, {x, y + 1}} {
+			if n[0] < 0 || n[0] >= m.Width || n[1] < 0 || n[1] >= m.Height {
+				continue
+			}
+			if nb := b.bit[n[1]*m.Width+n[0]]; nb > b.bit[i] {
+				b.next[b.bit[i]].set(nb)
+			}
+		}
+	}
+
+	for i, pl := range b.pls {
+		mv := bitMove{pl: i}
+		first := b.bit[pl.cells[0]]
+		for _, c := range pl.cells {
+			mv.mask.set(b.bit[c])
+			first = min(first, b.bit[c])
+		}
+		b.anchor[first][pl.piece] = append(b.anchor[first][pl.piece], mv)
+		b.single = b.single || len(pl.cells) == 1
+	}
+	return b
+}
+
+func (b *bitSolver) start() bitboard {
+	var board bitboard
+	for i := range b.m.data {
+		if b.m.data[i] != 0 {
+			board.set(b.bit[i])
+		}
+	}
+	return board
+}
+
+// search fills the board, left is the number of copies of each piece not
+// placed yet.
+func (b *bitSolver) search(s *search, worker int, board bitboard, left []int, sol []int) bool {
+	if s.stopped() {
+		return false
+	}
+
+	if board == b.full {
+		if !s.complete(left) {
+			return true
+		}
+		return s.emit(worker, func() *Matrix {
+			res := b.m.duplicate()
+			for _, i := range sol {
+				res.apply(b.pieces, b.pls[i])
+			}
+			return res
+		})
+	}
+
+	empty := board.firstEmpty()
+	// The neighbours with a lower bit are already filled, when the others are
+	// filled too the cell is isolated.
+	if !b.single && board.or(b.next[empty]) == board {
+		return true
+	}
+
+	options := b.anchor[empty]
+	for p := range options {
+		if left[p] == 0 {
+			continue
+		}
+		left[p]--
+		for _, mv := range options[p] {
+			if mv.mask.overlaps(board) {
+				continue
+			}
+			if !b.search(s, worker, board.or(mv.mask), left, append(sol, mv.pl)) {
+				left[p]++
+				return false
+			}
+		}
+		left[p]++
+	}
+	return true
+}
+
+// bitboard runs the bitboard search with one worker per piece placed on the
+// first empty cell. The board must have at most 128 cells.
+func (s *search) bitboard(wg *sync.WaitGroup, m *Matrix) {
+	b := newBitSolver(m, s.pieces, s.placements(m))
+	board := b.start()
+	if board == b.full {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b.search(s, 0, board, slices.Clone(s.most), nil)
+		}()
+		return
+	}
+
+	options := b.anchor[board.firstEmpty()]
+	size := 0
+	for _, n := range s.most {
+		size += n
+	}
+	s.workers = make([]atomic.Int64, len(s.pieces))
+	wg.Add(len(s.pieces))
+	for p := range s.pieces {
+		go func(p int) {
+			defer wg.Done()
+			left := slices.Clone(s.most)
+			left[p]--
+			for _, mv := range options[p] {
+				if mv.mask.overlaps(board) {
+					continue
+				}
+				sol := make([]int, 1, size)
+				sol[0] = mv.pl
+				if !b.search(s, p, board.or(mv.mask), left, sol) {
+					return
+				}
+			}
+		}(p)
+	}
+}
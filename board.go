@@ -0,0 +1,144 @@
+package psolver
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// boardFiles are the built-in boards, in the format of LoadBoard.
+//
+//go:embed boards/*.json
+var boardFiles embed.FS
+
+// Board is a puzzle board definition, the shape of the board with its blocked
+// cells and the names of the cells that can be blocked by label, like the
+// "D14" and "M3" cells of a calendar.
+type Board struct {
+	Name   string
+	Width  int
+	Height int
+
+	// Blocked cells are never part of the puzzle.
+	Blocked []bool
+	// Labels of the cells, empty for the cells without a name.
+	Labels []string
+}
+
+// boardFile is the JSON form of a board definition, see LoadBoard.
+type boardFile struct {
+	Name   string   `json:"name"`
+	Layout []string `json:"layout"`
+}
+
+// NewBoard creates a board from its layout, see LoadBoard for the format.
+func NewBoard(name string, layout []string) (*Board, error) {
+	b := &Board{
+		Name:   name,
+		Height: len(layout),
+	}
+	seen := map[string]struct{}{}
+	for j, row := range layout {
+		cells := strings.Fields(row)
+		if j == 0 {
+			b.Width = len(cells)
+		}
+		if len(cells) != b.Width {
+			return nil, fmt.Errorf("row %d has %d cells, expected %d", j+1, len(cells), b.Width)
+		}
+
+		for _, c := range cells {
+			switch c {
+			case "#":
+				b.Blocked = append(b.Blocked, true)
+				b.Labels = append(b.Labels, "")
+			case ".":
+				b.Blocked = append(b.Blocked, false)
+				b.Labels = append(b.Labels, "")
+			default:
+				if _, ok := seen[c]; ok {
+					return nil, fmt.Errorf("duplicate label %q", c)
+				}
+				seen[c] = struct{}{}
+				b.Blocked = append(b.Blocked, false)
+				b.Labels = append(b.Labels, c)
+			}
+		}
+	}
+
+	if b.Width == 0 {
+		return nil, fmt.Errorf("empty board %q", name)
+	}
+	return b, nil
+}
+
+// LoadBoard reads a JSON board definition, with the name of the board and
+// its layout, one string per row. The cells of a row are separated by spaces,
+// "." is an open cell, "#" is a blocked cell and anything else is the label of
+// an open cell:
+//
+//	{
+//	  "name": "example",
+//	  "layout": [
+//	    "M1 M2 M3 #",
+//	    "D1 D2 .  ."
+//	  ]
+//	}
+func LoadBoard(r io.Reader) (*Board, error) {
+	var f boardFile
+	if err := json.NewDecoder(r).Decode(&f); err != nil {
+		return nil, err
+	}
+	return NewBoard(f.Name, f.Layout)
+}
+
+// builtinBoard loads the built-in board with the name from boardFiles.
+func builtinBoard(name string) (*Board, error) {
+	f, err := boardFiles.Open("boards/" + name + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("%q is invalid board", name)
+	}
+	defer f.Close()
+	return LoadBoard(f)
+}
+
+// mustBoard is builtinBoard for the boards of the calendars.
+func mustBoard(name string) *Board {
+	b, err := builtinBoard(name)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// Matrix returns an empty matrix of the board, with the blocked cells filled.
+func (b *Board) Matrix() *Matrix {
+	m := NewMatrix(b.Width, b.Height)
+	for i := range b.Blocked {
+		if b.Blocked[i] {
+			m.data[i] = 'O'
+		}
+	}
+	return m
+}
+
+// Block returns the matrix of the board with the labelled cells filled too.
+func (b *Board) Block(labels ...string) (*Matrix, error) {
+	m := b.Matrix()
+	for _, l := range labels {
+		idx := -1
+		for i := range b.Labels {
+			if b.Labels[i] == l {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			return nil, fmt.Errorf("%q is invalid label", l)
+		}
+		m.data[idx] = 'O'
+	}
+	return m, nil
+}
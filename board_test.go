@@ -0,0 +1,57 @@
+package psolver
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadBoard(t *testing.T) {
+	in := `{"name": "small", "layout": ["A B #", ". C ."]}`
+	b, err := LoadBoard(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("LoadBoard failed: %v", err)
+	}
+	if b.Name != "small" || b.Width != 3 || b.Height != 2 {
+		t.Errorf("Unexpected board %q %dx%d", b.Name, b.Width, b.Height)
+	}
+
+	m, err := b.Block("B", "C")
+	if err != nil {
+		t.Fatalf("Block failed: %v", err)
+	}
+	if expected := ".OO\n.O.\n"; m.String() != expected {
+		t.Errorf("Expected:\n%q\nGot:\n%q", expected, m.String())
+	}
+
+	if _, err := b.Block("D"); err == nil {
+		t.Error("Expected error for unknown label")
+	}
+}
+
+func TestLoadBoardInvalid(t *testing.T) {
+	for _, in := range []string{
+		`{"name": "ragged", "layout": ["A B", ". . ."]}`,
+		`{"name": "duplicate", "layout": ["A A"]}`,
+		`{"name": "empty", "layout": []}`,
+		`not json`,
+	} {
+		if _, err := LoadBoard(strings.NewReader(in)); err == nil {
+			t.Errorf("Expected error for %s", in)
+		}
+	}
+}
+
+func TestBuiltinBoards(t *testing.T) {
+	for _, name := range append(CalendarBoards(), "square") {
+		b, err := builtinBoard(name)
+		if err != nil {
+			t.Fatalf("builtinBoard %s failed: %v", name, err)
+		}
+		if b.Name != name {
+			t.Errorf("Expected board %q, got %q", name, b.Name)
+		}
+	}
+	if _, err := builtinBoard("missing"); err == nil {
+		t.Error("Expected error for an unknown board")
+	}
+}
@@ -1,74 +1,282 @@
 package psolver
 
-import "fmt"
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
-type PersianCalendar struct {
+	"github.com/mshafiee/jalali"
+)
+
+// Calendar is a calendar puzzle, a board with the cells of the date blocked.
+type Calendar interface {
+	// SetTime blocks the cells of the date of t in loc, or in the location of
+	// t when loc is nil, and sets the footer of the board to the date. The
+	// error is a *DateError when a field of the date is not on the board.
+	SetTime(t time.Time, loc *time.Location) error
+	// Board returns the board with the cells of the current date blocked and
+	// the labels of the date cells, see Matrix.Labels.
+	Board() *Matrix
+	// Years returns the first and the last year of the board, zeros when the
+	// board has no year cells.
+	Years() (int, int)
+}
+
+// calendar is the board of a calendar with the cells of the current date
+// blocked, shared by the calendars. They only differ in the base of the year
+// cells and the names of their months and weekdays.
+type calendar struct {
 	Matrix
+	board  *Board
+	locale *Locale
+	base   int
+	names  func(l *Locale) CalendarNames
 }
 
-var mapping = []string{
-	"W1", "W7", "D1", "D2", "D3", "D4", "D5", "D6", "M1", "M2",
-	"W2", "D7", "D8", "D9", "D10", "D11", "D12", "D13", "M3", "M4",
-	"W3", "D14", "D15", "D16", "D17", "D18", "D19", "D20", "M5", "M6",
-	"W4", "D21", "D22", "D23", "D24", "D25", "D26", "D27", "M7", "M8",
-	"W5", "D28", "D29", "D30", "D31", "Y1", "Y2", "Y3", "M9", "M10",
-	"W6", "Y4", "Y5", "Y6", "Y7", "Y8", "Y9", "Y10", "M11", "M12",
-	"E1", "E2", "E3", "E4",
+func newCalendar(b *Board, base int, names func(l *Locale) CalendarNames) calendar {
+	c := calendar{
+		Matrix: *b.Matrix(),
+		board:  b,
+		base:   base,
+		names:  names,
+	}
+	c.SetLocale(LocaleEnglish)
+	return c
 }
 
-func (p *PersianCalendar) SetDate(WD, D, M, Y int) error {
-	w := fmt.Sprintf("W%d", WD)
-	m := fmt.Sprintf("M%d", M)
-	d := fmt.Sprintf("D%d", D)
-	y := fmt.Sprintf("Y%d", Y-1403)
-	data := [4]int{-1, -1, -1, -1}
-	for i := range mapping {
-		if mapping[i] == w {
-			data[0] = i
-		}
+// setDate blocks the date and sets the footer, the year cells start after
+// the base of the calendar.
+func (c *calendar) setDate(WD, D, M, Y int) error {
+	if err := setDate(&c.Matrix, c.board, c.base, WD, D, M, Y); err != nil {
+		return err
+	}
+	c.Footer = c.locale.date(c.names(c.locale), D, M, Y)
+	return nil
+}
 
-		if mapping[i] == m {
-			data[1] = i
-		}
+// SetLocale sets the language of the labels of the cells and of the footer of
+// the next dates, LocaleEnglish by default.
+func (c *calendar) SetLocale(l *Locale) {
+	c.locale = l
+	c.Labels = cellLabels(c.board, c.base, l, c.names(l))
+}
 
-		if mapping[i] == d {
-			data[2] = i
-		}
+func (c *calendar) Board() *Matrix {
+	return &c.Matrix
+}
+
+func (c *calendar) Years() (int, int) {
+	return years(c.board, c.base)
+}
 
-		if mapping[i] == y {
-			data[3] = i
+// PersianCalendar is the calendar board for the Jalali dates.
+type PersianCalendar struct {
+	calendar
+}
+
+// The year cells of the boards, Y1 is the year after the base.
+const (
+	persianBase   = 1403
+	gregorianBase = 2024
+)
+
+// DateError is returned when a field of the date has no cell on the board.
+type DateError struct {
+	Field    string // weekday, day, month or year
+	Value    int
+	Min, Max int
+}
+
+func (e *DateError) Error() string {
+	return fmt.Sprintf("%s %d is out of range, expected %d to %d", e.Field, e.Value, e.Min, e.Max)
+}
+
+var dateFields = []struct {
+	name, prefix string
+}{
+	{"weekday", "W"},
+	{"day", "D"},
+	{"month", "M"},
+	{"year", "Y"},
+}
+
+// NewPersianBoard returns the board definition of the calendar, the 10x7
+// board of boards/persian.json with weekday (W), day (D), month (M) and year
+// (Y) cells.
+func NewPersianBoard() *Board {
+	return mustBoard("persian")
+}
+
+// labelRange returns the smallest and the largest number of the labels with
+// the prefix, like 1 and 31 for the days.
+func labelRange(b *Board, prefix string) (int, int) {
+	lo, hi := 0, 0
+	for _, l := range b.Labels {
+		n, err := strconv.Atoi(strings.TrimPrefix(l, prefix))
+		if err != nil || !strings.HasPrefix(l, prefix) {
+			continue
+		}
+		if lo == 0 || n < lo {
+			lo = n
 		}
+		hi = max(hi, n)
 	}
+	return lo, hi
+}
+
+// years returns the years of the year cells of the board, after base.
+func years(b *Board, base int) (int, int) {
+	lo, hi := labelRange(b, "Y")
+	if hi == 0 {
+		return 0, 0
+	}
+	return lo + base, hi + base
+}
 
-	for i := range data {
-		if data[i] < 0 {
-			return fmt.Errorf("invalid input")
+// cellLabels returns the text of the date cells of the board in the locale,
+// like "14" for D14 and the name of the month for M3. The other cells have no
+// text.
+func cellLabels(b *Board, base int, l *Locale, names CalendarNames) []string {
+	res := make([]string, len(b.Labels))
+	for i, label := range b.Labels {
+		if label == "" {
+			continue
+		}
+		n, err := strconv.Atoi(label[1:])
+		if err != nil || n < 1 {
+			continue
+		}
+		switch label[0] {
+		case 'W':
+			if n <= len(names.Weekdays) {
+				res[i] = names.Weekdays[n-1]
+			}
+		case 'D':
+			res[i] = l.Number(n)
+		case 'M':
+			if n <= len(names.Months) {
+				res[i] = names.Months[n-1]
+			}
+		case 'Y':
+			res[i] = l.Number(base + n)
 		}
 	}
+	return res
+}
 
-	for i := range p.Matrix.data {
-		p.Matrix.data[i] = 0
+// setDate blocks the cells of the date on the board into m, the year cells
+// start after base. The fields without any cell on the board are ignored.
+// The footer is left to the calendar.
+func setDate(m *Matrix, b *Board, base, WD, D, M, Y int) error {
+	values := []int{WD, D, M, Y - base}
+	labels := make([]string, 0, len(dateFields))
+	for i, f := range dateFields {
+		lo, hi := labelRange(b, f.prefix)
+		if hi == 0 {
+			continue
+		}
+		l := f.prefix + strconv.Itoa(values[i])
+		if !slices.Contains(b.Labels, l) {
+			if f.prefix == "Y" {
+				return &DateError{Field: f.name, Value: Y, Min: lo + base, Max: hi + base}
+			}
+			return &DateError{Field: f.name, Value: values[i], Min: lo, Max: hi}
+		}
+		labels = append(labels, l)
 	}
-	p.Matrix.data[data[0]] = 'O'
-	p.Matrix.data[data[1]] = 'O'
-	p.Matrix.data[data[2]] = 'O'
-	p.Matrix.data[data[3]] = 'O'
-
-	p.Matrix.data[64] = 'O'
-	p.Matrix.data[65] = 'O'
-	p.Matrix.data[66] = 'O'
-	p.Matrix.data[67] = 'O'
-	p.Matrix.data[68] = 'O'
-	p.Matrix.data[69] = 'O'
 
+	blocked, err := b.Block(labels...)
+	if err != nil {
+		return err
+	}
+	copy(m.data, blocked.data)
 	return nil
 }
 
+// SetDate blocks the date in the Jalali calendar, the weekday is 1 for Shanbe
+// and Y is the full year.
+func (p *PersianCalendar) SetDate(WD, D, M, Y int) error {
+	return p.setDate(WD, D, M, Y)
+}
+
+// JalaliDate returns the weekday, the day, the month and the full year of t
+// in the Jalali calendar, the weekday is 1 for Shanbe, the Saturday.
+func JalaliDate(t time.Time) (int, int, int, int) {
+	j := jalali.ToJalali(t)
+	return (int(t.Weekday())+1)%7 + 1, j.Day(), int(j.Month()), j.Year()
+}
+
+// SetTime blocks the Jalali date of t.
+func (p *PersianCalendar) SetTime(t time.Time, loc *time.Location) error {
+	if loc != nil {
+		t = t.In(loc)
+	}
+	return p.SetDate(JalaliDate(t))
+}
+
 func NewPersianCalendar() *PersianCalendar {
-	m := NewMatrix(10, 7)
-	p := &PersianCalendar{
-		Matrix: *m,
+	return NewPersianCalendarFrom(NewPersianBoard())
+}
+
+// NewPersianCalendarFrom creates a Persian calendar on a custom board, the
+// board should have the W1..W7, D1..D31, M1..M12 and Y1..Y10 labels.
+func NewPersianCalendarFrom(b *Board) *PersianCalendar {
+	return &PersianCalendar{
+		calendar: newCalendar(b, persianBase, func(l *Locale) CalendarNames { return l.Jalali }),
 	}
+}
 
-	return p
+// NewCalendar creates a Persian calendar on a custom board.
+//
+// Deprecated: Use NewPersianCalendarFrom.
+func NewCalendar(b *Board) *PersianCalendar {
+	return NewPersianCalendarFrom(b)
+}
+
+// GregorianCalendar is the calendar board for the Gregorian dates, the months
+// come first like on most physical calendar puzzles.
+type GregorianCalendar struct {
+	calendar
+}
+
+// NewGregorianBoard returns the board definition of the Gregorian calendar,
+// the 10x7 board of boards/gregorian.json with the same shape and labels as
+// the Persian one. The week starts on Monday, W1. Y1..Y9 follow the last
+// days, Y10 is the bottom left cell at (0, 6), and every date of the ten years
+// has a solution.
+func NewGregorianBoard() *Board {
+	return mustBoard("gregorian")
+}
+
+// SetDate blocks the date, the weekday is 1 for Monday and Y is the full year.
+func (g *GregorianCalendar) SetDate(WD, D, M, Y int) error {
+	return g.setDate(WD, D, M, Y)
+}
+
+// GregorianDate returns the weekday, the day, the month and the year of t,
+// the weekday is 1 for Monday.
+func GregorianDate(t time.Time) (int, int, int, int) {
+	return (int(t.Weekday())+6)%7 + 1, t.Day(), int(t.Month()), t.Year()
+}
+
+// SetTime blocks the date of t.
+func (g *GregorianCalendar) SetTime(t time.Time, loc *time.Location) error {
+	if loc != nil {
+		t = t.In(loc)
+	}
+	return g.SetDate(GregorianDate(t))
+}
+
+func NewGregorianCalendar() *GregorianCalendar {
+	return NewGregorianCalendarFrom(NewGregorianBoard())
+}
+
+// NewGregorianCalendarFrom creates a Gregorian calendar on a custom board, with
+// the same labels as NewPersianCalendarFrom.
+func NewGregorianCalendarFrom(b *Board) *GregorianCalendar {
+	return &GregorianCalendar{
+		calendar: newCalendar(b, gregorianBase, func(l *Locale) CalendarNames { return l.Gregorian }),
+	}
 }
@@ -0,0 +1,166 @@
+package psolver
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestGregorianCalendar(t *testing.T) {
+	var cal Calendar = NewGregorianCalendar()
+	// A Friday, the 5th day of the week.
+	if err := cal.SetTime(time.Date(2025, 3, 14, 12, 0, 0, 0, time.UTC), nil); err != nil {
+		t.Fatalf("SetTime failed: %v", err)
+	}
+
+	want, err := NewGregorianBoard().Block("W5", "D14", "M3", "Y1")
+	if err != nil {
+		t.Fatalf("Block failed: %v", err)
+	}
+	want.Footer = "2025-03-14"
+	if cal.Board().String() != want.String() {
+		t.Errorf("Expected\n%s\ngot\n%s", want, cal.Board())
+	}
+
+	labels := cal.Board().Labels
+	for i, want := range map[int]string{0: "Jan", 2: "Mar", 6: "Mon", 33: "14", 48: "2025", 60: "2034", 19: ""} {
+		if labels[i] != want {
+			t.Errorf("Expected label %q for cell %d, got %q", want, i, labels[i])
+		}
+	}
+
+	if first, last := cal.Years(); first != 2025 || last != 2034 {
+		t.Errorf("Expected years 2025 to 2034, got %d to %d", first, last)
+	}
+	for _, year := range []int{2024, 2035} {
+		err := cal.SetTime(time.Date(year, 1, 1, 12, 0, 0, 0, time.UTC), nil)
+		de, ok := err.(*DateError)
+		if !ok {
+			t.Fatalf("Expected DateError for %d, got %v", year, err)
+		}
+		if de.Field != "year" || de.Value != year || de.Min != 2025 || de.Max != 2034 {
+			t.Errorf("Unexpected error %+v", de)
+		}
+	}
+}
+
+func TestGregorianCalendarSolvable(t *testing.T) {
+	if testing.Short() {
+		t.Skip("solves every date of the board")
+	}
+	cal := NewGregorianCalendar()
+	first, last := cal.Years()
+	for d := time.Date(first, 1, 1, 12, 0, 0, 0, time.UTC); d.Year() <= last; d = d.AddDate(0, 0, 1) {
+		if err := cal.SetTime(d, nil); err != nil {
+			t.Fatalf("SetTime failed: %v", err)
+		}
+		if _, ok := <-SolveContext(context.Background(), cal.Board(), New12(), SolveOptions{Engine: EngineAuto, Limit: 1}); !ok {
+			t.Errorf("No solution for %s", cal.Board().Footer)
+		}
+	}
+}
+
+func TestSetTimeLocation(t *testing.T) {
+	tehran := time.FixedZone("IRST", 3*3600+1800)
+	// Still the 20th in UTC, already Nowruz in Tehran.
+	now := time.Date(2026, 3, 20, 22, 0, 0, 0, time.UTC)
+
+	cal := NewPersianCalendar()
+	if err := cal.SetTime(now, tehran); err != nil {
+		t.Fatalf("SetTime failed: %v", err)
+	}
+	if cal.Footer != "1405-01-01" {
+		t.Errorf("Expected 1405-01-01, got %s", cal.Footer)
+	}
+
+	if err := cal.SetTime(now, nil); err != nil {
+		t.Fatalf("SetTime failed: %v", err)
+	}
+	if cal.Footer != "1404-12-29" {
+		t.Errorf("Expected 1404-12-29, got %s", cal.Footer)
+	}
+}
+
+func TestSetDateError(t *testing.T) {
+	tests := []struct {
+		WD, D, M, Y int
+		field       string
+	}{
+		{WD: 0, D: 1, M: 1, Y: 1404, field: "weekday"},
+		{WD: 1, D: 32, M: 1, Y: 1404, field: "day"},
+		{WD: 1, D: 1, M: 13, Y: 1404, field: "month"},
+		{WD: 1, D: 1, M: 1, Y: 1414, field: "year"},
+	}
+
+	cal := NewPersianCalendar()
+	for _, tt := range tests {
+		err := cal.SetDate(tt.WD, tt.D, tt.M, tt.Y)
+		de, ok := err.(*DateError)
+		if !ok {
+			t.Fatalf("Expected DateError, got %v", err)
+		}
+		if de.Field != tt.field {
+			t.Errorf("Expected field %s, got %s", tt.field, de.Field)
+		}
+	}
+}
+
+func TestPersianCalendarSetTime(t *testing.T) {
+	// 2025-03-21 is 1404-01-01, a Friday, Jome is the 7th day of the week.
+	cal := NewPersianCalendar()
+	if err := cal.SetTime(time.Date(2025, 3, 21, 12, 0, 0, 0, time.UTC), nil); err != nil {
+		t.Fatalf("SetTime failed: %v", err)
+	}
+
+	want := NewPersianCalendar()
+	if err := want.SetDate(7, 1, 1, 1404); err != nil {
+		t.Fatalf("SetDate failed: %v", err)
+	}
+	if cal.Board().String() != want.Board().String() {
+		t.Errorf("Expected\n%s\ngot\n%s", want.Board(), cal.Board())
+	}
+}
+
+func TestDateParts(t *testing.T) {
+	// A Friday, 1404-01-01 in the Jalali calendar.
+	d := time.Date(2025, 3, 21, 12, 0, 0, 0, time.UTC)
+	if wd, day, month, year := JalaliDate(d); wd != 7 || day != 1 || month != 1 || year != 1404 {
+		t.Errorf("Unexpected Jalali date %d %d-%d-%d", wd, year, month, day)
+	}
+	if wd, day, month, year := GregorianDate(d); wd != 5 || day != 21 || month != 3 || year != 2025 {
+		t.Errorf("Unexpected Gregorian date %d %d-%d-%d", wd, year, month, day)
+	}
+}
+
+func TestNewCalendarBoard(t *testing.T) {
+	for _, name := range CalendarBoards() {
+		b, pieces, err := NewCalendarBoard(name)
+		if err != nil {
+			t.Fatalf("NewCalendarBoard(%q) failed: %v", name, err)
+		}
+
+		cal := NewGregorianCalendarFrom(b)
+		if err := cal.SetTime(time.Date(2025, 2, 28, 12, 0, 0, 0, time.UTC), nil); err != nil {
+			t.Fatalf("%s: SetTime failed: %v", name, err)
+		}
+		if cal.Board().Footer != "2025-02-28" {
+			t.Errorf("%s: unexpected footer %q", name, cal.Board().Footer)
+		}
+
+		area := 0
+		for _, p := range pieces {
+			points, _ := p.Position(Point{}, 0)
+			area += len(points)
+		}
+		if area != cal.Board().EmptyCells() {
+			t.Fatalf("%s: the pieces cover %d cells, the board has %d", name, area, cal.Board().EmptyCells())
+		}
+		if _, ok := <-SolveContext(context.Background(), cal.Board(), pieces, SolveOptions{Engine: EngineAuto, Limit: 1}); !ok {
+			t.Errorf("%s: no solution for 2025-02-28", name)
+		}
+	}
+
+	if _, _, err := NewCalendarBoard("nope"); err == nil {
+		t.Error("Expected error for an unknown board")
+	}
+}
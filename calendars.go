@@ -0,0 +1,61 @@
+package psolver
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// namedPieces returns the pentominoes with the names, in the order of New12.
+func namedPieces(names string) []Piece {
+	var res []Piece
+	for _, p := range New12() {
+		for i := range len(names) {
+			if names[i] == p.Name() {
+				res = append(res, p)
+			}
+		}
+	}
+	return res
+}
+
+// calendarBoards are the pieces of the built-in calendar boards, the boards
+// are in boards/. The month-day board is the 7x7 board of the classic
+// A-Puzzle-A-Day, with the months and the days only. The weekday board is the
+// 7x8 board with the weekdays too, Sunday to Wednesday after the last days and
+// Thursday to Saturday under them.
+var calendarBoards = map[string]func() []Piece{
+	"persian":   New12,
+	"gregorian": New12,
+	"month-day": func() []Piece {
+		return append(namedPieces("LNPUVYZ"), mustShape('R', "RRR/RRR"))
+	},
+	"weekday": func() []Piece {
+		return append(namedPieces("LNPUVYZ"),
+			mustShape('i', "iiii"),
+			mustShape('l', "l../lll"),
+			mustShape('s', ".ss/ss."),
+		)
+	},
+}
+
+// CalendarBoards returns the names of the built-in calendar boards.
+func CalendarBoards() []string {
+	return slices.Sorted(maps.Keys(calendarBoards))
+}
+
+// NewCalendarBoard returns the built-in calendar board with the name and the
+// pieces that solve it. The boards without the weekday or the year cells
+// ignore those fields of the date.
+func NewCalendarBoard(name string) (*Board, []Piece, error) {
+	pieces, ok := calendarBoards[name]
+	if !ok {
+		return nil, nil, fmt.Errorf("%q is invalid board", name)
+	}
+
+	b, err := builtinBoard(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	return b, pieces(), nil
+}
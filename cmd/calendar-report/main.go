@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	psolver "github.com/fzerorubigd/pentomino-solver"
+	"github.com/mshafiee/jalali"
+)
+
+type entry struct {
+	Date      string `json:"date"`
+	Weekday   int    `json:"weekday"`
+	Day       int    `json:"day"`
+	Month     int    `json:"month"`
+	Year      int    `json:"year"`
+	Solutions int64  `json:"solutions"`
+	Status    string `json:"status"`
+}
+
+type report struct {
+	Dates      []entry  `json:"dates"`
+	Unsolvable []string `json:"unsolvable"`
+	Hard       []string `json:"hard"`
+}
+
+func writeCSV(w io.Writer, r *report) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"date", "weekday", "day", "month", "year", "solutions", "status"}); err != nil {
+		return err
+	}
+	for _, e := range r.Dates {
+		rec := []string{
+			e.Date,
+			strconv.Itoa(e.Weekday),
+			strconv.Itoa(e.Day),
+			strconv.Itoa(e.Month),
+			strconv.Itoa(e.Year),
+			strconv.FormatInt(e.Solutions, 10),
+			e.Status,
+		}
+		if err := cw.Write(rec); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func main() {
+	var from, to, few int
+	var jalaliDate bool
+	var format, output, boardName string
+	flag.IntVar(&from, "from", 1, "The first year of the report, for Persian 1=1404 and for Gregorian 1=2025")
+	flag.IntVar(&to, "to", 10, "The last year of the report, max 10")
+	flag.IntVar(&few, "few", 10, "Dates with at most this many solutions are reported as hard")
+	flag.BoolVar(&jalaliDate, "jalali", false, "Use jalali calendar")
+	flag.StringVar(&boardName, "board", "", "The built-in calendar board, one of "+strings.Join(psolver.CalendarBoards(), ", ")+", empty for persian with -jalali and gregorian otherwise")
+	flag.StringVar(&format, "format", "csv", "The report format, csv or json")
+	flag.StringVar(&output, "output", "", "The report file, empty for stdout")
+	flag.Parse()
+
+	if boardName == "" {
+		boardName = "gregorian"
+		if jalaliDate {
+			boardName = "persian"
+		}
+	}
+	board, pie, err := psolver.NewCalendarBoard(boardName)
+	if err != nil {
+		fmt.Println("Error loading board:", err)
+		os.Exit(-1)
+	}
+
+	var cal psolver.Calendar = psolver.NewGregorianCalendarFrom(board)
+	dateParts := psolver.GregorianDate
+	first, last := 2025, 2034
+	if jalaliDate {
+		cal = psolver.NewPersianCalendarFrom(board)
+		dateParts = psolver.JalaliDate
+		first, last = 1404, 1413
+	}
+	// The boards without the year cells are the same every year, the report
+	// still covers the years from and to.
+	if f, l := cal.Years(); f != 0 {
+		first, last = f, l
+	}
+	years := last - first + 1
+
+	if from < 1 || to > years || from > to {
+		fmt.Printf("The year range should be between 1 and %d\n", years)
+		os.Exit(-1)
+	}
+	if format != "csv" && format != "json" {
+		fmt.Printf("%q is invalid format\n", format)
+		os.Exit(-1)
+	}
+
+	base := first - 1
+	start := time.Date(base+from, 1, 1, 12, 0, 0, 0, time.UTC)
+	if jalaliDate {
+		start = jalali.Date(base+from, jalali.Farvardin, 1, 12, 0, 0, 0, time.UTC).ToGregorian()
+	}
+
+	r := &report{
+		Unsolvable: []string{},
+		Hard:       []string{},
+	}
+	hardest := -1
+	for date := start; ; date = date.AddDate(0, 0, 1) {
+		W, D, M, Y := dateParts(date)
+		if Y > base+to {
+			break
+		}
+
+		if err := cal.SetTime(date, nil); err != nil {
+			fmt.Printf("Error setting date %d-%02d-%02d: %v\n", Y, M, D, err)
+			os.Exit(-1)
+		}
+		res, err := psolver.Count(context.Background(), cal.Board(), pie)
+		if err != nil {
+			fmt.Println("Error counting:", err)
+			os.Exit(-1)
+		}
+
+		e := entry{
+			Date:      fmt.Sprintf("%d-%02d-%02d", Y, M, D),
+			Weekday:   W,
+			Day:       D,
+			Month:     M,
+			Year:      Y,
+			Solutions: res.Total,
+			Status:    "ok",
+		}
+		switch {
+		case res.Total == 0:
+			e.Status = "unsolvable"
+			r.Unsolvable = append(r.Unsolvable, e.Date)
+		case res.Total <= int64(few):
+			e.Status = "hard"
+			r.Hard = append(r.Hard, e.Date)
+		}
+		if hardest < 0 || e.Solutions < r.Dates[hardest].Solutions {
+			hardest = len(r.Dates)
+		}
+		r.Dates = append(r.Dates, e)
+		fmt.Fprintf(os.Stderr, "%s: %d\n", e.Date, e.Solutions)
+	}
+
+	w := io.Writer(os.Stdout)
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			fmt.Printf("Error creating file %s: %v\n", output, err)
+			os.Exit(-1)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if format == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(r)
+	} else {
+		err = writeCSV(w, r)
+	}
+	if err != nil {
+		fmt.Println("Error writing report:", err)
+		os.Exit(-1)
+	}
+
+	fmt.Fprintf(os.Stderr, "%d dates, %d unsolvable, %d with at most %d solutions\n", len(r.Dates), len(r.Unsolvable), len(r.Hard), few)
+	if hardest >= 0 {
+		fmt.Fprintf(os.Stderr, "The hardest date is %s with %d solutions\n", r.Dates[hardest].Date, r.Dates[hardest].Solutions)
+	}
+}
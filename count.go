@@ -0,0 +1,58 @@
+package psolver
+
+import (
+	"context"
+	"fmt"
+)
+
+// CountResult is the number of solutions found by Count.
+type CountResult struct {
+	Total int64
+	// Workers is the number of solutions found by each worker, by its index.
+	// The engines start one worker for each piece, or for each placement with
+	// EngineDLX, on the first cell they fill.
+	Workers []int64
+}
+
+// Count returns the number of solutions without building the boards. When ctx
+// is cancelled it returns the solutions counted so far with the context error.
+func Count(ctx context.Context, m *Matrix, pieces []Piece) (*CountResult, error) {
+	return CountWithOptions(ctx, m, pieces, SolveOptions{})
+}
+
+// CountWithOptions is Count with the same options as SolveContext, Buffer is
+// ignored.
+func CountWithOptions(ctx context.Context, m *Matrix, pieces []Piece, opts SolveOptions) (*CountResult, error) {
+	return CountBag(ctx, m, NewBag(pieces...), opts)
+}
+
+// CountBag is CountWithOptions for a bag of pieces.
+func CountBag(ctx context.Context, m *Matrix, bag Bag, opts SolveOptions) (*CountResult, error) {
+	if err := opts.Engine.Check(m); err != nil {
+		return nil, err
+	}
+	s := newSearch(ctx, m, bag, opts, nil)
+	s.run(m, opts.Engine).Wait()
+	s.cancel()
+
+	res := &CountResult{
+		Workers: make([]int64, len(s.workers)),
+	}
+	for i := range s.workers {
+		res.Workers[i] = s.workers[i].Load()
+		res.Total += res.Workers[i]
+	}
+	return res, ctx.Err()
+}
+
+// String returns the total followed by the count of each worker that found a
+// solution.
+func (c *CountResult) String() string {
+	res := fmt.Sprintf("Total: %d\n", c.Total)
+	for i, n := range c.Workers {
+		if n > 0 {
+			res += fmt.Sprintf("Worker %d: %d\n", i, n)
+		}
+	}
+	return res
+}
@@ -0,0 +1,56 @@
+package psolver
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCount(t *testing.T) {
+	cal := NewPersianCalendar()
+	if err := cal.SetDate(1, 1, 1, 1404); err != nil {
+		t.Fatalf("SetDate failed: %v", err)
+	}
+
+	res, err := Count(context.Background(), &cal.Matrix, New12())
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if res.Total != 1210 {
+		t.Errorf("Expected 1210 solutions, got %d", res.Total)
+	}
+
+	var sum int64
+	for _, n := range res.Workers {
+		sum += n
+	}
+	if sum != res.Total {
+		t.Errorf("Workers sum %d, expected %d", sum, res.Total)
+	}
+	// One worker for each piece placed on the first empty cell.
+	if len(res.Workers) != 12 {
+		t.Errorf("Expected 12 workers, got %d", len(res.Workers))
+	}
+}
+
+func TestCountWithOptions(t *testing.T) {
+	res, err := CountWithOptions(context.Background(), NewMatrix(20, 3), New12(), SolveOptions{Engine: EngineAuto, Unique: true})
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if res.Total != 2 {
+		t.Errorf("Expected 2 solutions, got %d", res.Total)
+	}
+}
+
+func TestCountCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	res, err := Count(ctx, NewMatrix(10, 6), New12())
+	if err == nil {
+		t.Error("Expected the context error")
+	}
+	if res.Total != 0 {
+		t.Errorf("Expected no solution after cancel, got %d", res.Total)
+	}
+}
@@ -0,0 +1,250 @@
+package psolver
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// dlx is Knuth's dancing links representation of an exact cover problem. The
+// nodes are stored in parallel slices, index 0 is the root and the next
+// indexes are the column headers.
+//
+// A column with a limit may be covered by up to that many rows, it is not in
+// the header list so the search never requires it, and it is covered only
+// when the limit is reached.
+type dlx struct {
+	left, right, up, down []int
+	col                   []int
+	size                  []int
+	row                   []int // placement index of each node, -1 for headers
+	limit, used           []int // per column, limit is zero for exact columns
+}
+
+func newDLX(columns int, limit []int, rows [][]int) *dlx {
+	d := &dlx{
+		limit: limit,
+		used:  make([]int, columns+1),
+	}
+	for i := 0; i <= columns; i++ {
+		d.left = append(d.left, i-1)
+		d.right = append(d.right, i+1)
+		d.up = append(d.up, i)
+		d.down = append(d.down, i)
+		d.col = append(d.col, i)
+		d.size = append(d.size, 0)
+		d.row = append(d.row, -1)
+	}
+	prev := 0
+	for c := 1; c <= columns; c++ {
+		if limit[c] > 0 {
+			d.left[c], d.right[c] = c, c
+			continue
+		}
+		d.right[prev], d.left[c] = c, prev
+		prev = c
+	}
+	d.right[prev], d.left[0] = 0, prev
+
+	for r, cols := range rows {
+		start := len(d.col)
+		for i, c := range cols {
+			n := len(d.col)
+			d.col = append(d.col, c)
+			d.row = append(d.row, r)
+			d.size[c]++
+
+			d.up = append(d.up, d.up[c])
+			d.down = append(d.down, c)
+			d.down[d.up[c]] = n
+			d.up[c] = n
+
+			if i == 0 {
+				d.left = append(d.left, n)
+				d.right = append(d.right, n)
+				continue
+			}
+			d.left = append(d.left, n-1)
+			d.right = append(d.right, start)
+			d.right[n-1] = n
+			d.left[start] = n
+		}
+	}
+	return d
+}
+
+func (d *dlx) cover(c int) {
+	d.right[d.left[c]] = d.right[c]
+	d.left[d.right[c]] = d.left[c]
+	for i := d.down[c]; i != c; i = d.down[i] {
+		for j := d.right[i]; j != i; j = d.right[j] {
+			d.down[d.up[j]] = d.down[j]
+			d.up[d.down[j]] = d.up[j]
+			d.size[d.col[j]]--
+		}
+	}
+}
+
+func (d *dlx) uncover(c int) {
+	for i := d.up[c]; i != c; i = d.up[i] {
+		for j := d.left[i]; j != i; j = d.left[j] {
+			d.size[d.col[j]]++
+			d.down[d.up[j]] = j
+			d.up[d.down[j]] = j
+		}
+	}
+	d.right[d.left[c]] = c
+	d.left[d.right[c]] = c
+}
+
+// choose returns the column with the fewest rows, 0 when every column is covered.
+func (d *dlx) choose() int {
+	best := 0
+	for c := d.right[0]; c != 0; c = d.right[c] {
+		if best == 0 || d.size[c] < d.size[best] {
+			best = c
+		}
+	}
+	return best
+}
+
+// take covers the other columns of the row of node n, the columns with a
+// limit only when the row is the last one they take.
+func (d *dlx) take(n int) {
+	for j := d.right[n]; j != n; j = d.right[j] {
+		c := d.col[j]
+		if d.limit[c] > 0 {
+			d.used[c]++
+			if d.used[c] < d.limit[c] {
+				continue
+			}
+		}
+		d.cover(c)
+	}
+}
+
+// release undoes take.
+func (d *dlx) release(n int) {
+	for j := d.left[n]; j != n; j = d.left[j] {
+		c := d.col[j]
+		if d.limit[c] > 0 {
+			d.used[c]--
+			if d.used[c] < d.limit[c]-1 {
+				continue
+			}
+		}
+		d.uncover(c)
+	}
+}
+
+func (d *dlx) search(s *search, sol []int, found func([]int) bool) bool {
+	if s.stopped() {
+		return false
+	}
+
+	c := d.choose()
+	if c == 0 {
+		return found(sol)
+	}
+	if d.size[c] == 0 {
+		return true
+	}
+
+	d.cover(c)
+	defer d.uncover(c)
+	for r := d.down[c]; r != c; r = d.down[r] {
+		d.take(r)
+		more := d.search(s, append(sol, d.row[r]), found)
+		d.release(r)
+		if !more {
+			return false
+		}
+	}
+	return true
+}
+
+// dlx solves the board as an exact cover problem, one column per piece and
+// one per empty cell. A piece with more than one or optional copies has a
+// column with a limit, the solutions without enough copies of it are dropped
+// at the end. The rows of the first chosen column are searched in parallel,
+// each worker with its own copy of the links.
+func (s *search) dlx(wg *sync.WaitGroup, m *Matrix) {
+	pls := s.placements(m)
+	cellCol := make([]int, len(m.data))
+	columns := len(s.pieces)
+	limit := make([]int, columns+1)
+	for k := range s.pieces {
+		if s.least[k] != 1 || s.most[k] != 1 {
+			limit[k+1] = s.most[k]
+		}
+	}
+	for i := range m.data {
+		if m.data[i] == 0 {
+			columns++
+			cellCol[i] = columns
+			limit = append(limit, 0)
+		}
+	}
+
+	rows := make([][]int, len(pls))
+	for i, pl := range pls {
+		cols := make([]int, 0, len(pl.cells)+1)
+		cols = append(cols, pl.piece+1)
+		for _, c := range pl.cells {
+			cols = append(cols, cellCol[c])
+		}
+		rows[i] = cols
+	}
+
+	workers := map[int]int{}
+	found := func(sol []int) bool {
+		left := slices.Clone(s.most)
+		for _, r := range sol {
+			left[pls[r].piece]--
+		}
+		if !s.complete(left) {
+			return true
+		}
+
+		worker := 0
+		if len(sol) > 0 {
+			worker = workers[sol[0]]
+		}
+		return s.emit(worker, func() *Matrix {
+			board := m.duplicate()
+			for _, r := range sol {
+				board.apply(s.pieces, pls[r])
+			}
+			return board
+		})
+	}
+
+	root := newDLX(columns, limit, rows)
+	c := root.choose()
+	if c == 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			found(nil)
+		}()
+		return
+	}
+
+	// The workers by the placement of the first row they take.
+	for r := root.down[c]; r != c; r = root.down[r] {
+		workers[root.row[r]] = len(workers)
+	}
+	s.workers = make([]atomic.Int64, len(workers))
+
+	// The node indexes are the same in every copy of the links.
+	for r := root.down[c]; r != c; r = root.down[r] {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			d := newDLX(columns, limit, rows)
+			d.cover(c)
+			d.take(n)
+			d.search(s, []int{d.row[n]}, found)
+		}(r)
+	}
+}
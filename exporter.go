@@ -2,6 +2,7 @@ package psolver
 
 import (
 	"fmt"
+	"html"
 	"image"
 	icolor "image/color"
 	"image/draw"
@@ -10,8 +11,8 @@ import (
 
 	"github.com/fatih/color"
 	"golang.org/x/image/font"
-	"golang.org/x/image/font/basicfont"
 	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
 )
 
 // Exporter is the interface for exporting a matrix to an io.Writer
@@ -30,32 +31,20 @@ func (s *StringExporter) Export(m *Matrix, w io.Writer) error {
 
 // ColorStringExporter exports the matrix using ANSI colors
 type ColorStringExporter struct {
-	colorMap map[byte]*color.Color
+	// Theme of the pieces, DefaultTheme when nil.
+	Theme *Theme
 }
 
 // NewColorStringExporter creates a new ColorStringExporter with default colors
 func NewColorStringExporter() *ColorStringExporter {
 	return &ColorStringExporter{
-		colorMap: map[byte]*color.Color{
-			'F': color.New().AddBgRGB(230, 25, 75).AddRGB(230, 25, 75),     // Red
-			'I': color.New().AddBgRGB(60, 180, 75).AddRGB(60, 180, 75),     // Green
-			'L': color.New().AddBgRGB(255, 225, 25).AddRGB(255, 225, 25),   // Yellow
-			'N': color.New().AddBgRGB(0, 130, 200).AddRGB(0, 130, 200),     // Blue
-			'P': color.New().AddBgRGB(245, 130, 48).AddRGB(245, 130, 48),   // Orange
-			'T': color.New().AddBgRGB(145, 30, 180).AddRGB(145, 30, 180),   // Purple
-			'U': color.New().AddBgRGB(70, 240, 240).AddRGB(70, 240, 240),   // Cyan
-			'V': color.New().AddBgRGB(240, 50, 230).AddRGB(240, 50, 230),   // Magenta
-			'W': color.New().AddBgRGB(210, 245, 60).AddRGB(210, 245, 60),   // Lime
-			'X': color.New().AddBgRGB(250, 190, 212).AddRGB(250, 190, 212), // Pink
-			'Y': color.New().AddBgRGB(0, 128, 128).AddRGB(0, 128, 128),     // Teal
-			'Z': color.New().AddBgRGB(220, 190, 255).AddRGB(220, 190, 255), // Lavender
-			'O': color.New().AddBgRGB(0, 0, 0).AddRGB(0, 0, 0),             // Balck
-		},
+		Theme: DefaultTheme(),
 	}
 }
 
 // Export writes the matrix with colors to the writer
 func (c *ColorStringExporter) Export(m *Matrix, w io.Writer) error {
+	colors := themeOrDefault(c.Theme).colors(m)
 	for j := 0; j < m.Height; j++ {
 		for i := 0; i < m.Width; i++ {
 			val := m.data[j*m.Width+i]
@@ -64,14 +53,10 @@ func (c *ColorStringExporter) Export(m *Matrix, w io.Writer) error {
 					return err
 				}
 			} else {
-				if col, ok := c.colorMap[val]; ok {
-					if _, err := col.Fprint(w, " "+string(val)); err != nil {
-						return err
-					}
-				} else {
-					if _, err := io.WriteString(w, " "+string(val)); err != nil {
-						return err
-					}
+				rgb := colors[val]
+				col := color.New().AddBgRGB(int(rgb.R), int(rgb.G), int(rgb.B)).AddRGB(int(rgb.R), int(rgb.G), int(rgb.B))
+				if _, err := col.Fprint(w, " "+string(val)); err != nil {
+					return err
 				}
 			}
 		}
@@ -85,27 +70,22 @@ func (c *ColorStringExporter) Export(m *Matrix, w io.Writer) error {
 // SVGExporter exports the matrix as an SVG image
 type SVGExporter struct {
 	CellSize int
-	colorMap map[byte]string
+	// Radius rounds the corners of the pieces and Gap leaves a space between
+	// them, in pixels.
+	Radius, Gap int
+	// Locale of the footer, nil for a left to right footer.
+	Locale *Locale
+	// FooterAlign is the alignment of the footer.
+	FooterAlign Align
+	// Theme of the image, DefaultTheme when nil.
+	Theme *Theme
 }
 
 // NewSVGExporter creates a new SVGExporter with default settings
 func NewSVGExporter() *SVGExporter {
 	return &SVGExporter{
 		CellSize: 20,
-		colorMap: map[byte]string{
-			'F': "#E6194B", // Red
-			'I': "#3CB44B", // Green
-			'L': "#FFE119", // Yellow
-			'N': "#0082C8", // Blue
-			'P': "#F58230", // Orange
-			'T': "#911EB4", // Purple
-			'U': "#46F0F0", // Cyan
-			'V': "#F032E6", // Magenta
-			'W': "#D2F53C", // Lime
-			'X': "#FABED4", // Pink
-			'Y': "#008080", // Teal
-			'Z': "#DCBEFF", // Lavender
-		},
+		Theme:    DefaultTheme(),
 	}
 }
 
@@ -121,28 +101,64 @@ func (s *SVGExporter) Export(m *Matrix, w io.Writer) error {
 		return err
 	}
 
-	// Background (optional, but good for transparency handling if needed)
-	// fmt.Fprintf(w, "<rect width=\"100%%\" height=\"100%%\" fill=\"white\"/>\n")
+	theme := themeOrDefault(s.Theme)
+	if theme.Background.A != 0 {
+		if _, err := fmt.Fprintf(w, "<rect width=\"100%%\" height=\"100%%\" fill=\"%s\"%s/>\n", theme.Background.hex(), opacity("fill", theme.Background)); err != nil {
+			return err
+		}
+	}
 
-	for j := 0; j < m.Height; j++ {
-		for i := 0; i < m.Width; i++ {
-			val := m.data[j*m.Width+i]
-			if val != 0 {
-				color, ok := s.colorMap[val]
-				if !ok {
-					color = "black" // Fallback
-				}
-				x := i * s.CellSize
-				y := j * s.CellSize
-				if _, err := fmt.Fprintf(w, "<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"%s\" stroke=\"black\" stroke-width=\"1\"/>\n", x, y, s.CellSize, s.CellSize, color); err != nil {
-					return err
-				}
+	// Each piece is drawn with a single outline.
+	colors := theme.colors(m)
+	for _, r := range regions(m) {
+		x := r.cells[0] % m.Width * s.CellSize
+		y := r.cells[0] / m.Width * s.CellSize
+		if r.val == 0 {
+			// The empty cells only have the faded label.
+			if err := s.writeLabel(w, x, y, r.label, theme.Text.faded()); err != nil {
+				return err
+			}
+			continue
+		}
+
+		color := colors[r.val]
+		if r.label != "" {
+			// The cells not covered by a piece show their label, like the
+			// date on a physical calendar board.
+			color = theme.cell()
+		}
+		path := &svgPath{}
+		tracePath(path, r.loops(m.Width), float32(s.CellSize), float32(s.Gap)/2, float32(s.Radius))
+		if _, err := fmt.Fprintf(w, "<path d=\"%s\" fill=\"%s\"%s fill-rule=\"evenodd\" stroke=\"%s\"%s stroke-width=\"1\"/>\n",
+			path, color.hex(), opacity("fill", color), theme.Border.hex(), opacity("stroke", theme.Border)); err != nil {
+			return err
+		}
+		if r.label != "" {
+			if err := s.writeLabel(w, x, y, r.label, theme.Text); err != nil {
+				return err
 			}
 		}
 	}
 
 	if m.Footer != "" {
-		if _, err := fmt.Fprintf(w, "<text x=\"%d\" y=\"%d\" font-family=\"monospace\" font-size=\"15\" fill=\"black\">%s</text>\n", 10, height-5, m.Footer); err != nil {
+		rtl := s.Locale != nil && s.Locale.RTL
+		x, anchor := 10, "start"
+		switch {
+		case s.FooterAlign == AlignCenter:
+			x, anchor = width/2, "middle"
+		case s.FooterAlign == AlignEnd:
+			anchor = "end"
+		}
+		if s.FooterAlign != AlignCenter && !s.FooterAlign.left(rtl) {
+			x = width - 10
+		}
+		direction := ""
+		if rtl {
+			// The start of a right to left text is on its right.
+			direction = " direction=\"rtl\""
+		}
+		if _, err := fmt.Fprintf(w, "<text x=\"%d\" y=\"%d\" font-family=\"monospace\" font-size=\"15\" fill=\"%s\"%s%s text-anchor=\"%s\">%s</text>\n",
+			x, height-5, theme.Text.hex(), opacity("fill", theme.Text), direction, anchor, html.EscapeString(m.Footer)); err != nil {
 			return err
 		}
 	}
@@ -153,81 +169,206 @@ func (s *SVGExporter) Export(m *Matrix, w io.Writer) error {
 	return nil
 }
 
+// writeLabel writes the label in the middle of the cell at x, y.
+func (s *SVGExporter) writeLabel(w io.Writer, x, y int, label string, c Color) error {
+	_, err := fmt.Fprintf(w, "<text x=\"%d\" y=\"%d\" font-family=\"sans-serif\" font-size=\"%d\" text-anchor=\"middle\" dominant-baseline=\"central\" fill=\"%s\"%s>%s</text>\n",
+		x+s.CellSize/2, y+s.CellSize/2, max(s.CellSize*3/10, 1), c.hex(), opacity("fill", c), html.EscapeString(label))
+	return err
+}
+
+// opacity returns the opacity attribute of the color, empty when it is opaque
+// or transparent.
+func opacity(attr string, c Color) string {
+	if c.A == 0 || c.A == 0xFF {
+		return ""
+	}
+	return fmt.Sprintf(" %s-opacity=\"%.3g\"", attr, float64(c.A)/0xFF)
+}
+
 // PNGExporter exports the matrix as a PNG image
 type PNGExporter struct {
 	CellSize int
-	colorMap map[byte]icolor.Color
+	// Radius rounds the corners of the pieces and Gap leaves a space between
+	// them, in pixels.
+	Radius, Gap int
+	// Font of the labels and the footer, DefaultFont when nil. The sizes of
+	// the text grow with CellSize. The Persian text needs a font with the
+	// Arabic presentation forms.
+	Font *Font
+	// Face, when set, draws the labels and the footer in its own size
+	// instead of Font.
+	Face font.Face
+	// Locale of the footer, nil for a left to right footer.
+	Locale *Locale
+	// FooterAlign is the alignment of the footer.
+	FooterAlign Align
+	// Theme of the image, DefaultTheme when nil.
+	Theme *Theme
 }
 
-// NewPNGExporter creates a new PNGExporter with default settings
+// NewPNGExporter creates a new PNGExporter with default settings, the cells
+// of 32 pixels keep the labels readable.
 func NewPNGExporter() *PNGExporter {
 	return &PNGExporter{
-		CellSize: 20,
-		colorMap: map[byte]icolor.Color{
-			'F': icolor.RGBA{0xE6, 0x19, 0x4B, 0xFF}, // Red
-			'I': icolor.RGBA{0x3C, 0xB4, 0x4B, 0xFF}, // Green
-			'L': icolor.RGBA{0xFF, 0xE1, 0x19, 0xFF}, // Yellow
-			'N': icolor.RGBA{0x00, 0x82, 0xC8, 0xFF}, // Blue
-			'P': icolor.RGBA{0xF5, 0x82, 0x30, 0xFF}, // Orange
-			'T': icolor.RGBA{0x91, 0x1E, 0xB4, 0xFF}, // Purple
-			'U': icolor.RGBA{0x46, 0xF0, 0xF0, 0xFF}, // Cyan
-			'V': icolor.RGBA{0xF0, 0x32, 0xE6, 0xFF}, // Magenta
-			'W': icolor.RGBA{0xD2, 0xF5, 0x3C, 0xFF}, // Lime
-			'X': icolor.RGBA{0xFA, 0xBE, 0xD4, 0xFF}, // Pink
-			'Y': icolor.RGBA{0x00, 0x80, 0x80, 0xFF}, // Teal
-			'Z': icolor.RGBA{0xDC, 0xBE, 0xFF, 0xFF}, // Lavender
-		},
+		CellSize: 32,
+		Theme:    DefaultTheme(),
+	}
+}
+
+// faces returns the faces of the labels and of the footer.
+func (p *PNGExporter) faces() (font.Face, font.Face, error) {
+	if p.Face != nil {
+		return p.Face, p.Face, nil
+	}
+
+	f := p.Font
+	if f == nil {
+		f = DefaultFont()
+	}
+	label, err := f.Face(max(float64(p.CellSize)*0.35, 1))
+	if err != nil {
+		return nil, nil, err
+	}
+	footer, err := f.Face(max(float64(p.CellSize)*0.65, 1))
+	if err != nil {
+		return nil, nil, err
+	}
+	return label, footer, nil
+}
+
+// checkGlyphs returns an error when the face has no glyph for a rune of the
+// text, like the Persian script in Go Regular, instead of drawing boxes.
+func (p *PNGExporter) checkGlyphs(face font.Face, text string) error {
+	for _, r := range visual(text, p.rtl()) {
+		if _, ok := face.GlyphAdvance(r); !ok {
+			return fmt.Errorf("the font has no glyph for %q, set a font with the script of %q", r, text)
+		}
+	}
+	return nil
+}
+
+func (p *PNGExporter) rtl() bool {
+	return p.Locale != nil && p.Locale.RTL
+}
+
+// mask returns the coverage of the outline of a piece, see tracePath.
+func (p *PNGExporter) mask(b image.Rectangle, loops [][]Point, inset, radius float32) *image.Alpha {
+	z := vector.NewRasterizer(b.Dx(), b.Dy())
+	tracePath(z, loops, float32(p.CellSize), inset, max(radius, 0))
+	a := image.NewAlpha(b)
+	z.Draw(a, b, image.Opaque, image.Point{})
+	return a
+}
+
+// fill draws a piece and its border, the ring of a pixel around it. Both are
+// drawn over the background only, so the translucent colors are not darkened
+// by the border under them.
+func (p *PNGExporter) fill(img *image.RGBA, loops [][]Point, inset, radius float32, border, c icolor.Color) {
+	b := img.Bounds()
+	ring := p.mask(b, loops, inset, radius)
+	inner := p.mask(b, loops, inset+1, radius-1)
+	for i, a := range inner.Pix {
+		ring.Pix[i] -= min(ring.Pix[i], a)
+	}
+	draw.DrawMask(img, b, image.NewUniform(border), image.Point{}, ring, b.Min, draw.Over)
+	draw.DrawMask(img, b, image.NewUniform(c), image.Point{}, inner, b.Min, draw.Over)
+}
+
+// drawLabel draws the label in the middle of the cell, cut at its borders.
+func (p *PNGExporter) drawLabel(img *image.RGBA, face font.Face, cell image.Rectangle, label string, c icolor.Color) {
+	d := &font.Drawer{
+		Dst:  img.SubImage(cell.Inset(1)).(*image.RGBA),
+		Src:  image.NewUniform(c),
+		Face: face,
+	}
+	label = visual(label, p.rtl())
+	width := d.MeasureString(label)
+	metrics := d.Face.Metrics()
+	d.Dot = fixed.Point26_6{
+		X: fixed.I(cell.Min.X+cell.Dx()/2) - width/2,
+		Y: fixed.I(cell.Min.Y+cell.Dy()/2) + (metrics.Ascent-metrics.Descent)/2,
 	}
+	d.DrawString(label)
 }
 
 // Export writes the matrix as a PNG to the writer
 func (p *PNGExporter) Export(m *Matrix, w io.Writer) error {
+	theme := themeOrDefault(p.Theme)
+	img, err := p.render(m, theme, theme.colors(m))
+	if err != nil {
+		return err
+	}
+	return png.Encode(w, img)
+}
+
+// render draws the matrix with the colors of the pieces.
+func (p *PNGExporter) render(m *Matrix, theme *Theme, colors map[byte]Color) (*image.RGBA, error) {
 	width := m.Width * p.CellSize
 	height := m.Height * p.CellSize
+	labelFace, footerFace, err := p.faces()
+	if err != nil {
+		return nil, err
+	}
+	for i, c := range m.data {
+		if c == 0 || c == 'O' {
+			if err := p.checkGlyphs(labelFace, m.label(i)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := p.checkGlyphs(footerFace, m.Footer); err != nil {
+		return nil, err
+	}
 	footerHeight := 0
 	if m.Footer != "" {
-		footerHeight = 20
+		footerHeight = max(20, footerFace.Metrics().Height.Ceil()+7)
 		height += footerHeight
 	}
 
 	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(theme.Background), image.Point{}, draw.Src)
 
-	// Draw pieces
-	for j := 0; j < m.Height; j++ {
-		for i := 0; i < m.Width; i++ {
-			val := m.data[j*m.Width+i]
-			if val != 0 {
-				c, ok := p.colorMap[val]
-				if !ok {
-					c = icolor.Black // Fallback
-				}
-				x := i * p.CellSize
-				y := j * p.CellSize
-				rect := image.Rect(x, y, x+p.CellSize, y+p.CellSize)
-				draw.Draw(img, rect, &image.Uniform{c}, image.Point{}, draw.Src)
-
-				// Simple border
-				borderColor := icolor.Black
-				// Top
-				for k := 0; k < p.CellSize; k++ {
-					img.Set(x+k, y, borderColor)
-					img.Set(x+k, y+p.CellSize-1, borderColor)
-					img.Set(x, y+k, borderColor)
-					img.Set(x+p.CellSize-1, y+k, borderColor)
-				}
-			}
+	// Draw pieces, each with a single outline
+	for _, r := range regions(m) {
+		x := r.cells[0] % m.Width * p.CellSize
+		y := r.cells[0] / m.Width * p.CellSize
+		cell := image.Rect(x, y, x+p.CellSize, y+p.CellSize)
+		if r.val == 0 {
+			// The empty cells only have the faded label.
+			p.drawLabel(img, labelFace, cell, r.label, theme.Text.faded())
+			continue
+		}
+
+		c := colors[r.val]
+		if r.label != "" {
+			// The cells not covered by a piece show their label, like the
+			// date on a physical calendar board.
+			c = theme.cell()
+		}
+		loops := r.loops(m.Width)
+		inset := float32(p.Gap) / 2
+		p.fill(img, loops, inset, float32(p.Radius), theme.Border, c)
+		if r.label != "" {
+			p.drawLabel(img, labelFace, cell, r.label, theme.Text)
 		}
 	}
 
 	if m.Footer != "" {
 		d := &font.Drawer{
 			Dst:  img,
-			Src:  image.Black,
-			Face: basicfont.Face7x13,
-			Dot:  fixed.Point26_6{X: fixed.I(10), Y: fixed.I(height - 5)},
+			Src:  image.NewUniform(theme.Text),
+			Face: footerFace,
+			Dot:  fixed.Point26_6{X: fixed.I(10), Y: fixed.I(height - footerHeight/4)},
 		}
-		d.DrawString(m.Footer)
+		footer := visual(m.Footer, p.rtl())
+		switch {
+		case p.FooterAlign == AlignCenter:
+			d.Dot.X = fixed.I(width/2) - d.MeasureString(footer)/2
+		case !p.FooterAlign.left(p.rtl()):
+			d.Dot.X = fixed.I(width-10) - d.MeasureString(footer)
+		}
+		d.DrawString(footer)
 	}
 
-	return png.Encode(w, img)
+	return img, nil
 }
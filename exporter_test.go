@@ -2,6 +2,7 @@ package psolver
 
 import (
 	"bytes"
+	"image/png"
 	"io"
 	"testing"
 
@@ -104,9 +105,9 @@ func TestSVGExporter(t *testing.T) {
 	if !bytes.Contains(buf.Bytes(), []byte("<svg width=\"60\" height=\"60\"")) {
 		t.Error("SVG header missing or incorrect dimensions")
 	}
-	// Check for rects
-	if !bytes.Contains(buf.Bytes(), []byte("<rect")) {
-		t.Error("SVG should contain rectangles")
+	// Check for the outlines of the pieces
+	if got := bytes.Count(buf.Bytes(), []byte("<path")); got != 3 {
+		t.Errorf("SVG should contain a path for each piece, got %d", got)
 	}
 	// Check for specific color (F is Red #E6194B)
 	if !bytes.Contains(buf.Bytes(), []byte("#E6194B")) {
@@ -116,6 +117,15 @@ func TestSVGExporter(t *testing.T) {
 	if !bytes.Contains(buf.Bytes(), []byte("#3CB44B")) {
 		t.Error("SVG should contain color for I (#3CB44B)")
 	}
+	// The footer, read from a user file, is escaped like the labels.
+	m.Footer = "<a & b>"
+	buf.Reset()
+	if err := exporter.Export(m, &buf); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte(">&lt;a &amp; b&gt;</text>")) {
+		t.Error("SVG should contain the escaped footer")
+	}
 }
 
 func TestPNGExporter(t *testing.T) {
@@ -137,3 +147,103 @@ func TestPNGExporter(t *testing.T) {
 		t.Error("Output should be a PNG file")
 	}
 }
+
+func TestPNGExporterTranslucent(t *testing.T) {
+	m := NewMatrix(1, 1)
+	m.data[0] = 'F'
+
+	e := NewPNGExporter()
+	e.Theme = DefaultTheme()
+	e.Theme.Background = Color{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}
+	e.Theme.Pieces["F"] = Color{G: 0xFF, A: 0x80}
+	img, err := e.render(m, e.Theme, e.Theme.colors(m))
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	// The piece is half green over the white background, not over the border.
+	got := img.RGBAAt(e.CellSize/2, e.CellSize/2)
+	if got.R < 0x70 || got.G != 0xFF || got.B < 0x70 {
+		t.Errorf("Expected light green, got %v", got)
+	}
+}
+
+func TestSVGExporterLabels(t *testing.T) {
+	cal := NewGregorianCalendar()
+	if err := cal.SetDate(5, 14, 3, 2025); err != nil {
+		t.Fatalf("SetDate failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := NewSVGExporter().Export(cal.Board(), &buf); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	for _, label := range []string{">Fri<", ">14<", ">Mar<", ">2025<", ">Jan<"} {
+		if !bytes.Contains(buf.Bytes(), []byte(label)) {
+			t.Errorf("SVG should contain the label %s", label)
+		}
+	}
+
+	// The cells covered by a piece have no label.
+	m := cal.Board().duplicate()
+	m.data[0] = 'F'
+	buf.Reset()
+	if err := NewSVGExporter().Export(m, &buf); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if bytes.Contains(buf.Bytes(), []byte(">Jan<")) {
+		t.Error("SVG should not contain the label of a covered cell")
+	}
+}
+
+func TestPNGExporterFont(t *testing.T) {
+	m := NewMatrix(3, 2)
+	m.Footer = "2025-03-14"
+
+	for _, size := range []int{20, 64} {
+		e := NewPNGExporter()
+		e.CellSize = size
+		e.FooterAlign = AlignCenter
+		var buf bytes.Buffer
+		if err := e.Export(m, &buf); err != nil {
+			t.Fatalf("Export failed: %v", err)
+		}
+		img, err := png.Decode(&buf)
+		if err != nil {
+			t.Fatalf("Decode failed: %v", err)
+		}
+		// The footer grows with the cells.
+		footer := img.Bounds().Dy() - 2*size
+		if footer < 20 || footer < size*3/4 {
+			t.Errorf("Footer of %d pixels for cells of %d pixels", footer, size)
+		}
+	}
+
+	// The labels of the default cells are readable.
+	label, _, err := NewPNGExporter().faces()
+	if err != nil {
+		t.Fatalf("faces failed: %v", err)
+	}
+	if h := label.Metrics().Height.Ceil(); h < 11 {
+		t.Errorf("Labels of %d pixels with the default cells", h)
+	}
+
+	if _, err := ParseFont([]byte("no font")); err == nil {
+		t.Error("Expected error for an invalid font")
+	}
+}
+
+func TestParseAlign(t *testing.T) {
+	for name, want := range map[string]Align{"start": AlignStart, "center": AlignCenter, "end": AlignEnd} {
+		got, err := ParseAlign(name)
+		if err != nil || got != want {
+			t.Errorf("ParseAlign(%q) = %v, %v", name, got, err)
+		}
+	}
+	if _, err := ParseAlign("middle"); err == nil {
+		t.Error("Expected error for an unknown alignment")
+	}
+
+	if !AlignStart.left(false) || AlignStart.left(true) || AlignEnd.left(false) || !AlignEnd.left(true) {
+		t.Error("Unexpected side of the footer")
+	}
+}
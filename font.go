@@ -0,0 +1,91 @@
+package psolver
+
+import (
+	"fmt"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+)
+
+// Font is a TrueType or OpenType font, the PNG exporter makes its faces in
+// the sizes that fit the cells.
+type Font struct {
+	font *opentype.Font
+}
+
+// ParseFont parses the data of a TrueType or OpenType font, like a font file
+// embedded in the binary.
+func ParseFont(data []byte) (*Font, error) {
+	f, err := opentype.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	return &Font{font: f}, nil
+}
+
+var defaultFont = sync.OnceValue(func() *Font {
+	f, err := ParseFont(goregular.TTF)
+	if err != nil {
+		panic(err)
+	}
+	return f
+})
+
+// DefaultFont returns the Go Regular font embedded in the package. It has no
+// Persian script, the PNG exporter returns an error for the Persian text.
+func DefaultFont() *Font {
+	return defaultFont()
+}
+
+// Face returns the face of the font in the size in pixels.
+func (f *Font) Face(size float64) (font.Face, error) {
+	return opentype.NewFace(f.font, &opentype.FaceOptions{
+		Size:    size,
+		DPI:     72,
+		Hinting: font.HintingFull,
+	})
+}
+
+// NewFace returns the face of a TrueType or OpenType font, like a font with
+// the Persian script for LocalePersian, in the size in pixels.
+func NewFace(data []byte, size float64) (font.Face, error) {
+	f, err := ParseFont(data)
+	if err != nil {
+		return nil, err
+	}
+	return f.Face(size)
+}
+
+// Align is the alignment of the footer.
+type Align int
+
+const (
+	// AlignStart aligns the footer to the left, or to the right in a right to
+	// left locale.
+	AlignStart Align = iota
+	AlignCenter
+	// AlignEnd aligns the footer to the right, or to the left in a right to
+	// left locale.
+	AlignEnd
+)
+
+// ParseAlign returns the alignment with the name, start, center or end.
+func ParseAlign(name string) (Align, error) {
+	switch name {
+	case "start":
+		return AlignStart, nil
+	case "center":
+		return AlignCenter, nil
+	case "end":
+		return AlignEnd, nil
+	}
+	return 0, fmt.Errorf("%q is invalid alignment", name)
+}
+
+// left reports whether the text starts on the left, for the start and the end
+// alignments.
+func (a Align) left(rtl bool) bool {
+	return (a == AlignStart) != rtl
+}
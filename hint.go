@@ -0,0 +1,48 @@
+package psolver
+
+import (
+	"context"
+	"errors"
+	"sort"
+)
+
+// ErrNoSolution is returned when a board has no solution.
+var ErrNoSolution = errors.New("no solution")
+
+// Hint returns the board with n pieces of one of its solutions placed. It
+// analyses all the solutions, picks the smallest one in the order of the
+// letters on the board and places its pieces that are in the same place in
+// the most solutions first, so the forced pieces come before the others.
+// Limit in opts is ignored.
+func Hint(ctx context.Context, m *Matrix, pieces []Piece, n int, opts SolveOptions) (*Matrix, error) {
+	a, err := Analyze(ctx, m, pieces, opts)
+	if err != nil {
+		return nil, err
+	}
+	return a.Hint(m, n)
+}
+
+// Hint returns the board m, the board of the analysis, with n pieces of the
+// smallest solution placed, see Hint.
+func (a *Analysis) Hint(m *Matrix, n int) (*Matrix, error) {
+	if a.smallest == nil {
+		return nil, ErrNoSolution
+	}
+
+	order := placementsOf(m, a.smallest)
+	sort.SliceStable(order, func(i, j int) bool {
+		return a.Count(order[i]) > a.Count(order[j])
+	})
+
+	res := m.duplicate()
+	res.Footer = m.Footer
+	for _, pl := range order[:min(n, len(order))] {
+		first := pl.Cells[0].Y*m.Width + pl.Cells[0].X
+		for _, c := range pl.Cells {
+			res.data[c.Y*m.Width+c.X] = pl.Name
+			res.own(c.Y*m.Width+c.X, first)
+		}
+		res.pieces[pl.Name] = pl.State
+	}
+	return res, nil
+}
@@ -0,0 +1,63 @@
+package psolver
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestHint(t *testing.T) {
+	m := NewMatrix(5, 3)
+	res, err := Hint(context.Background(), m, smallSet(t, PieceP, PieceU, PieceV), 1, SolveOptions{})
+	if err != nil {
+		t.Fatalf("Hint failed: %v", err)
+	}
+
+	// The U is in the middle of two of the four solutions, the others are in
+	// a single one.
+	placed := map[byte]int{}
+	for _, c := range res.data {
+		placed[c]++
+	}
+	if placed['U'] != 5 || placed[0] != 10 {
+		t.Errorf("Expected only the U placed, got\n%s", res)
+	}
+	if _, ok := res.Pieces()['U']; !ok {
+		t.Error("Expected the state of U")
+	}
+
+	if m.String() != ".....\n.....\n.....\n" {
+		t.Errorf("The board is changed\n%s", m)
+	}
+}
+
+func TestHintCalendar(t *testing.T) {
+	cal := NewPersianCalendar()
+	if err := cal.SetDate(1, 1, 1, 1404); err != nil {
+		t.Fatalf("SetDate failed: %v", err)
+	}
+
+	res, err := Hint(context.Background(), &cal.Matrix, New12(), 3, SolveOptions{Engine: EngineAuto})
+	if err != nil {
+		t.Fatalf("Hint failed: %v", err)
+	}
+	if len(res.Pieces()) != 3 {
+		t.Errorf("Expected 3 pieces, got %d", len(res.Pieces()))
+	}
+	n := 0
+	for i, c := range res.data {
+		if c != cal.data[i] {
+			n++
+		}
+	}
+	if n != 15 {
+		t.Errorf("Expected 15 cells filled, got %d", n)
+	}
+}
+
+func TestHintNoSolution(t *testing.T) {
+	_, err := Hint(context.Background(), NewMatrix(5, 3), smallSet(t, PieceX, PieceI, PieceL), 1, SolveOptions{})
+	if !errors.Is(err, ErrNoSolution) {
+		t.Errorf("Expected ErrNoSolution, got %v", err)
+	}
+}
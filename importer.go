@@ -0,0 +1,179 @@
+package psolver
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Importer is the interface for reading a matrix from an io.Reader, the
+// counterpart of Exporter
+type Importer interface {
+	Import(r io.Reader) (*Matrix, error)
+}
+
+// StringImporter reads the matrix written by StringExporter
+type StringImporter struct {
+	// Pieces are the pieces that may be on the board, one copy of each, New12
+	// when empty. The letters that are not the name of a piece are blocked
+	// cells.
+	Pieces []Piece
+	// Bag is used in place of Pieces when it is set, with up to Count copies
+	// of each piece on the board.
+	Bag Bag
+}
+
+// Import reads the matrix and checks that the cells of each piece are made of
+// its copies, the state of the first copy is set in Pieces of the matrix.
+func (s *StringImporter) Import(r io.Reader) (*Matrix, error) {
+	m, err := parseMatrix(r)
+	if err != nil {
+		return nil, err
+	}
+
+	bag := s.Bag
+	if len(bag) == 0 && len(s.Pieces) > 0 {
+		bag = NewBag(s.Pieces...)
+	} else if len(bag) == 0 {
+		bag = NewBag(New12()...)
+	}
+	for _, item := range bag {
+		states, err := m.detect(item.Piece, max(item.Count, 1))
+		if err != nil {
+			return nil, err
+		}
+		if len(states) > 0 {
+			m.pieces[item.Piece.Name()] = states[0]
+		}
+	}
+	return m, nil
+}
+
+// ParseMatrix reads a board in the format of Matrix.String with the 12
+// pentominoes, see StringImporter.
+func ParseMatrix(r io.Reader) (*Matrix, error) {
+	return (&StringImporter{}).Import(r)
+}
+
+// parseMatrix reads a board in the format of Matrix.String, one line per row
+// with "." for the empty cells and a letter for the others. The rows end at
+// the first line with a different length or other characters, that line and
+// the lines after it are the footer.
+func parseMatrix(r io.Reader) (*Matrix, error) {
+	var rows, footer []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		switch {
+		case len(footer) > 0:
+			footer = append(footer, line)
+		case len(rows) == 0 && line == "":
+		case isRow(line) && (len(rows) == 0 || len(line) == len(rows[0])):
+			rows = append(rows, line)
+		default:
+			footer = append(footer, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("empty board")
+	}
+
+	m := NewMatrix(len(rows[0]), len(rows))
+	for j, row := range rows {
+		for i := range len(row) {
+			if row[i] != '.' {
+				m.data[j*m.Width+i] = row[i]
+			}
+		}
+	}
+	m.Footer = strings.TrimSpace(strings.Join(footer, "\n"))
+	return m, nil
+}
+
+func isRow(line string) bool {
+	if line == "" {
+		return false
+	}
+	for i := range len(line) {
+		c := line[i] | 0x20 // lower case
+		if line[i] != '.' && (c < 'a' || c > 'z') {
+			return false
+		}
+	}
+	return true
+}
+
+// detect returns the states of the copies of the piece from their cells on
+// the board, at most copies of them, and records the cells of each copy. It is
+// empty when the piece is not on the board.
+func (m *Matrix) detect(p Piece, copies int) ([]int, error) {
+	left := make([]bool, len(m.data))
+	n := 0
+	for i, c := range m.data {
+		if c == p.Name() {
+			left[i] = true
+			n++
+		}
+	}
+	if n == 0 {
+		return nil, nil
+	}
+
+	states, ok := m.cover(p, left, n, copies)
+	if !ok {
+		return nil, fmt.Errorf("%q is invalid piece placement", string(p.Name()))
+	}
+	return states, nil
+}
+
+// cover splits the n cells left into at most copies placements of the piece,
+// each one on the first cell left, and returns their states. The copies that
+// touch each other are told apart by trying the states in turn.
+func (m *Matrix) cover(p Piece, left []bool, n, copies int) ([]int, bool) {
+	if n == 0 {
+		return nil, true
+	}
+	if copies == 0 {
+		return nil, false
+	}
+
+	first := slices.Index(left, true)
+	for st := range p.States() {
+		points, err := p.Position(Point{X: first % m.Width, Y: first / m.Width}, st)
+		if err != nil || len(points) > n {
+			continue
+		}
+		got := make([]int, 0, len(points))
+		for _, pt := range points {
+			i := pt.Y*m.Width + pt.X
+			if pt.X < 0 || pt.X >= m.Width || pt.Y < 0 || pt.Y >= m.Height || !left[i] {
+				break
+			}
+			got = append(got, i)
+		}
+		if len(got) != len(points) {
+			continue
+		}
+
+		for _, i := range got {
+			left[i] = false
+		}
+		rest, ok := m.cover(p, left, n-len(got), copies-1)
+		for _, i := range got {
+			left[i] = true
+		}
+		if ok {
+			for _, i := range got {
+				m.own(i, got[0])
+			}
+			return append([]int{st}, rest...), true
+		}
+	}
+	return nil, false
+}
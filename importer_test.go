@@ -0,0 +1,103 @@
+package psolver
+
+import (
+	"bytes"
+	"context"
+	"maps"
+	"strings"
+	"testing"
+)
+
+func TestStringImporter(t *testing.T) {
+	var im Importer = &StringImporter{}
+	for sol := range SolveContext(context.Background(), NewMatrix(10, 6), New12(), SolveOptions{Engine: EngineAuto, Limit: 5}) {
+		var buf bytes.Buffer
+		if err := (&StringExporter{}).Export(sol, &buf); err != nil {
+			t.Fatalf("Export failed: %v", err)
+		}
+
+		m, err := im.Import(&buf)
+		if err != nil {
+			t.Fatalf("Import failed: %v", err)
+		}
+		if m.String() != sol.String() {
+			t.Errorf("Expected\n%s\ngot\n%s", sol, m)
+		}
+		if !maps.Equal(m.Pieces(), sol.Pieces()) {
+			t.Errorf("Expected states %v, got %v", sol.Pieces(), m.Pieces())
+		}
+	}
+}
+
+func TestParseMatrix(t *testing.T) {
+	cal := NewPersianCalendar()
+	if err := cal.SetDate(1, 14, 3, 1404); err != nil {
+		t.Fatalf("SetDate failed: %v", err)
+	}
+	cal.Footer = "2025-03-14"
+
+	m, err := ParseMatrix(strings.NewReader(cal.String()))
+	if err != nil {
+		t.Fatalf("ParseMatrix failed: %v", err)
+	}
+	if m.Width != 10 || m.Height != 7 {
+		t.Errorf("Expected 10x7, got %dx%d", m.Width, m.Height)
+	}
+	if m.Footer != cal.Footer {
+		t.Errorf("Expected footer %q, got %q", cal.Footer, m.Footer)
+	}
+	if m.String() != cal.String() {
+		t.Errorf("Expected\n%s\ngot\n%s", cal.String(), m.String())
+	}
+
+	if _, err := ParseMatrix(strings.NewReader("\n\n")); err == nil {
+		t.Error("Expected error for an empty board")
+	}
+}
+
+func TestStringImporterInvalid(t *testing.T) {
+	tests := []string{
+		"XX...\nX....\nX....\n",
+		"IIII.\n.....\n",
+		"IIIII\nI....\n",
+	}
+	for _, in := range tests {
+		if _, err := ParseMatrix(strings.NewReader(in)); err == nil {
+			t.Errorf("Expected error for\n%s", in)
+		}
+	}
+
+	m, err := (&StringImporter{Pieces: NewTetrominoes()}).Import(strings.NewReader("oo..\noo..\n"))
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if _, ok := m.Pieces()['o']; !ok {
+		t.Error("Expected the state of the square tetromino")
+	}
+}
+
+func TestStringImporterBag(t *testing.T) {
+	// Two L that touch, and a third L left in the bag.
+	in := "LLLLL\nLLLLL\n.....\n"
+	bag, err := ParseBag("L*3")
+	if err != nil {
+		t.Fatalf("ParseBag failed: %v", err)
+	}
+	if _, err := ParseMatrix(strings.NewReader(in)); err == nil {
+		t.Error("Expected error for two L with a single copy")
+	}
+
+	m, err := (&StringImporter{Bag: bag}).Import(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	left := bag.Remaining(m)
+	if len(left) != 1 || left[0].Count != 1 {
+		t.Errorf("Expected one L left, got %+v", left)
+	}
+
+	bag[0].Count = 1
+	if _, err := (&StringImporter{Bag: bag}).Import(strings.NewReader(in)); err == nil {
+		t.Error("Expected error for more copies than in the bag")
+	}
+}
@@ -0,0 +1,98 @@
+package psolver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CalendarNames are the names of the months and the weekdays of a calendar.
+type CalendarNames struct {
+	// Months from the first month of the year.
+	Months []string
+	// Weekdays from the first day of the week of the calendar, Monday in
+	// Gregorian and Shanbe in Jalali.
+	Weekdays []string
+}
+
+// Locale is the language of the labels of the calendar cells and of the
+// footer of the boards.
+type Locale struct {
+	Name string
+	// RTL is set for the languages written from right to left, the footer is
+	// aligned to the right and laid out from right to left.
+	RTL bool
+	// Digits are the digits from 0 to 9.
+	Digits [10]rune
+	// LongDate writes the footer with the name of the month, like
+	// "14 Khordad 1404", instead of "1404-03-14".
+	LongDate bool
+
+	Gregorian CalendarNames
+	Jalali    CalendarNames
+}
+
+// LocaleEnglish is the default locale, with the short names of the months
+// and the weekdays, the Jalali ones in Latin letters.
+var LocaleEnglish = &Locale{
+	Name:   "en",
+	Digits: [10]rune{'0', '1', '2', '3', '4', '5', '6', '7', '8', '9'},
+	Gregorian: CalendarNames{
+		Months:   []string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		Weekdays: []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"},
+	},
+	Jalali: CalendarNames{
+		Months:   []string{"Far", "Ord", "Kho", "Tir", "Mor", "Shr", "Meh", "Aba", "Aza", "Dey", "Bah", "Esf"},
+		Weekdays: []string{"Sha", "Yek", "Dos", "Ses", "Cha", "Pan", "Jom"},
+	},
+}
+
+// LocalePersian writes the names and the digits in the Persian script. The
+// PNG exporter needs a face with the Arabic presentation forms to draw it.
+var LocalePersian = &Locale{
+	Name:     "fa",
+	RTL:      true,
+	Digits:   [10]rune{'۰', '۱', '۲', '۳', '۴', '۵', '۶', '۷', '۸', '۹'},
+	LongDate: true,
+	Gregorian: CalendarNames{
+		Months:   []string{"ژانویه", "فوریه", "مارس", "آوریل", "مه", "ژوئن", "ژوئیه", "اوت", "سپتامبر", "اکتبر", "نوامبر", "دسامبر"},
+		Weekdays: []string{"دوشنبه", "سه‌شنبه", "چهارشنبه", "پنجشنبه", "جمعه", "شنبه", "یکشنبه"},
+	},
+	Jalali: CalendarNames{
+		Months:   []string{"فروردین", "اردیبهشت", "خرداد", "تیر", "مرداد", "شهریور", "مهر", "آبان", "آذر", "دی", "بهمن", "اسفند"},
+		Weekdays: []string{"شنبه", "یکشنبه", "دوشنبه", "سه‌شنبه", "چهارشنبه", "پنجشنبه", "جمعه"},
+	},
+}
+
+// ParseLocale returns the built-in locale with the name, en or fa.
+func ParseLocale(name string) (*Locale, error) {
+	for _, l := range []*Locale{LocaleEnglish, LocalePersian} {
+		if l.Name == name {
+			return l, nil
+		}
+	}
+	return nil, fmt.Errorf("%q is invalid locale", name)
+}
+
+// Number writes n with the digits of the locale.
+func (l *Locale) Number(n int) string {
+	return l.digits(strconv.Itoa(n))
+}
+
+// digits replaces the ASCII digits of s with the digits of the locale.
+func (l *Locale) digits(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return l.Digits[r-'0']
+		}
+		return r
+	}, s)
+}
+
+// date returns the footer of the date, Y is the full year.
+func (l *Locale) date(names CalendarNames, D, M, Y int) string {
+	if l.LongDate && M >= 1 && M <= len(names.Months) {
+		return fmt.Sprintf("%s %s %s", l.Number(D), names.Months[M-1], l.Number(Y))
+	}
+	return l.digits(fmt.Sprintf("%d-%02d-%02d", Y, M, D))
+}
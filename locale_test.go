@@ -0,0 +1,99 @@
+package psolver
+
+import (
+	"bytes"
+	"testing"
+
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+func TestParseLocale(t *testing.T) {
+	l, err := ParseLocale("fa")
+	if err != nil {
+		t.Fatalf("ParseLocale failed: %v", err)
+	}
+	if got := l.Number(1404); got != "۱۴۰۴" {
+		t.Errorf("Expected Persian digits, got %q", got)
+	}
+	if _, err := ParseLocale("de"); err == nil {
+		t.Error("Expected error for an unknown locale")
+	}
+}
+
+func TestPersianLocale(t *testing.T) {
+	cal := NewPersianCalendar()
+	cal.SetLocale(LocalePersian)
+	if err := cal.SetDate(1, 14, 3, 1404); err != nil {
+		t.Fatalf("SetDate failed: %v", err)
+	}
+	if cal.Footer != "۱۴ خرداد ۱۴۰۴" {
+		t.Errorf("Unexpected footer %q", cal.Footer)
+	}
+	// W1, the first cell, is Shanbe and M1 is Farvardin.
+	if cal.Labels[0] != "شنبه" || cal.Labels[8] != "فروردین" {
+		t.Errorf("Unexpected labels %q and %q", cal.Labels[0], cal.Labels[8])
+	}
+
+	cal.SetLocale(LocaleEnglish)
+	if err := cal.SetDate(1, 14, 3, 1404); err != nil {
+		t.Fatalf("SetDate failed: %v", err)
+	}
+	if cal.Footer != "1404-03-14" || cal.Labels[8] != "Far" {
+		t.Errorf("Unexpected footer %q and label %q", cal.Footer, cal.Labels[8])
+	}
+}
+
+func TestVisual(t *testing.T) {
+	// Khe joins the Re, the other letters do not join the next one.
+	want := string([]rune{0xFEA9, 0xFE8D, 0xFEA9, 0xFEAE, 0xFEA7})
+	if got := visual("خرداد", false); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+	if got := visual("۱۴ خرداد", true); got != want+" ۱۴" {
+		t.Errorf("Expected the words in the right to left order, got %q", got)
+	}
+	// Sin, Ye and Mim join on both sides.
+	want = string([]rune{0xFEE2, 0xFBFF, 0xFEB3})
+	if got := visual("سیم", false); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+	if got := visual("2025-03-14", true); got != "2025-03-14" {
+		t.Errorf("Expected the Latin text unchanged, got %q", got)
+	}
+}
+
+func TestPNGExporterFace(t *testing.T) {
+	face, err := NewFace(goregular.TTF, 12)
+	if err != nil {
+		t.Fatalf("NewFace failed: %v", err)
+	}
+	if _, err := NewFace([]byte("no font"), 12); err == nil {
+		t.Error("Expected error for an invalid font")
+	}
+
+	cal := NewPersianCalendar()
+	cal.SetLocale(LocalePersian)
+	if err := cal.SetDate(1, 14, 3, 1404); err != nil {
+		t.Fatalf("SetDate failed: %v", err)
+	}
+	e := NewPNGExporter()
+	e.Face = face
+	e.Locale = LocalePersian
+	var buf bytes.Buffer
+	// Go Regular has no Persian script, the boxes are not drawn.
+	if err := e.Export(cal.Board(), &buf); err == nil {
+		t.Error("Expected error for the Persian labels in Go Regular")
+	}
+
+	cal.SetLocale(LocaleEnglish)
+	if err := cal.SetDate(1, 14, 3, 1404); err != nil {
+		t.Fatalf("SetDate failed: %v", err)
+	}
+	e.Locale = LocaleEnglish
+	if err := e.Export(cal.Board(), &buf); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte("\x89PNG")) {
+		t.Error("Output should be a PNG file")
+	}
+}
@@ -0,0 +1,86 @@
+package psolver
+
+// oneSided is a piece limited to the rotations of its first state, as if it
+// can not be flipped over.
+type oneSided struct {
+	Piece
+	states []int // states of the piece that are rotations of its first state
+}
+
+// OneSided returns the piece limited to the rotations of its first state. The
+// pieces that are their own mirror image keep all of their states.
+func OneSided(p Piece) Piece {
+	first, err := p.Position(Point{}, 0)
+	if err != nil {
+		return p
+	}
+
+	rotations := map[string]struct{}{}
+	for _, t := range orientationTransforms[:4] {
+		moved := make([]Point, len(first))
+		for i, c := range first {
+			moved[i] = t(c)
+		}
+		rotations[shapeKey(moved)] = struct{}{}
+	}
+
+	res := &oneSided{Piece: p}
+	for st := range p.States() {
+		points, err := p.Position(Point{}, st)
+		if err != nil {
+			continue
+		}
+		if _, ok := rotations[shapeKey(points)]; ok {
+			res.states = append(res.states, st)
+		}
+	}
+	return res
+}
+
+func (o *oneSided) States() int {
+	return len(o.states)
+}
+
+func (o *oneSided) Position(ref Point, state int) ([]Point, error) {
+	if state < 0 || state >= len(o.states) {
+		return nil, ErrInvalidState
+	}
+	return o.Piece.Position(ref, o.states[state])
+}
+
+// mirrorNames are the names of the mirror images of the pentominoes, lower
+// case letters that are not the names of the tetrominoes or of the pieces of
+// the calendar boards. The mirror L is j, like the L and J tetrominoes.
+var mirrorNames = map[byte]byte{
+	'F': 'f',
+	'L': 'j',
+	'N': 'n',
+	'P': 'p',
+	'Y': 'y',
+	'Z': 'z',
+}
+
+// NewOneSided18 returns the 18 one-sided pentominoes, the rotations of the 12
+// pentominoes and of the mirror images of the F, L, N, P, Y and Z, named f,
+// j, n, p, y and z.
+func NewOneSided18() []Piece {
+	res := make([]Piece, 0, 18)
+	for _, p := range New12() {
+		one := OneSided(p)
+		res = append(res, one)
+		if one.States() == p.States() {
+			continue
+		}
+
+		first, _ := p.Position(Point{}, 0)
+		for i := range first {
+			first[i] = Point{X: -first[i].X, Y: first[i].Y}
+		}
+		mirror, err := NewPolyomino(mirrorNames[p.Name()], first)
+		if err != nil {
+			panic(err)
+		}
+		res = append(res, OneSided(mirror))
+	}
+	return res
+}
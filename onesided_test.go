@@ -0,0 +1,53 @@
+package psolver
+
+import "testing"
+
+func TestOneSided(t *testing.T) {
+	want := map[byte]int{
+		'F': 4, 'I': 2, 'L': 4, 'N': 4, 'P': 4, 'T': 4,
+		'U': 4, 'V': 4, 'W': 4, 'X': 1, 'Y': 4, 'Z': 2,
+	}
+	for _, p := range New12() {
+		if n := OneSided(p).States(); n != want[p.Name()] {
+			t.Errorf("Piece %s: expected %d states, got %d", string(p.Name()), want[p.Name()], n)
+		}
+	}
+}
+
+func TestNewOneSided18(t *testing.T) {
+	pieces := NewOneSided18()
+	if len(pieces) != 18 {
+		t.Fatalf("Expected 18 pieces, got %d", len(pieces))
+	}
+
+	names := map[byte]struct{}{}
+	shapes := map[string]struct{}{}
+	for _, p := range pieces {
+		names[p.Name()] = struct{}{}
+		for st := range p.States() {
+			points, err := p.Position(Point{}, st)
+			if err != nil {
+				t.Fatalf("Position failed: %v", err)
+			}
+			shapes[shapeKey(points)] = struct{}{}
+		}
+	}
+	if len(names) != 18 {
+		t.Errorf("Expected 18 names, got %d", len(names))
+	}
+	// The mirror pieces can be mixed with the tetrominoes and the calendar
+	// pieces.
+	_, weekday, err := NewCalendarBoard("weekday")
+	if err != nil {
+		t.Fatalf("NewCalendarBoard failed: %v", err)
+	}
+	for _, p := range append(NewTetrominoes(), weekday...) {
+		if _, ok := names[p.Name()]; ok && p.Name() >= 'a' {
+			t.Errorf("The mirror %s clashes with a built-in piece", string(p.Name()))
+		}
+	}
+	// Every fixed pentomino belongs to exactly one of the pieces.
+	if len(shapes) != 63 {
+		t.Errorf("Expected 63 fixed pentominoes, got %d", len(shapes))
+	}
+}
@@ -0,0 +1,222 @@
+package psolver
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// region is a piece on the board, the connected cells with the same value,
+// or a single uncovered cell with a label.
+type region struct {
+	val   byte
+	label string
+	cells []int
+}
+
+// regions returns the pieces and the blocked cells of the board, the cells
+// with a label are regions on their own. The copies of a piece that touch
+// each other are split by their placements, when the matrix knows them.
+func regions(m *Matrix) []region {
+	seen := make([]bool, len(m.data))
+	var res []region
+	for i, val := range m.data {
+		if seen[i] || (val == 0 && m.label(i) == "") {
+			continue
+		}
+		seen[i] = true
+
+		r := region{val: val, cells: []int{i}}
+		if val == 0 || (val == 'O' && m.label(i) != "") {
+			r.label = m.label(i)
+			res = append(res, r)
+			continue
+		}
+		for k := 0; k < len(r.cells); k++ {
+			x, y := r.cells[k]%m.Width, r.cells[k]/m.Width
+			for _, n := range []Point{{x - 1, y}, {x + 1, y}, {x, y - 1}, {x, y + 1}} {
+				if n.X < 0 || n.Y < 0 || n.X >= m.Width || n.Y >= m.Height {
+					continue
+				}
+				j := n.Y*m.Width + n.X
+				if !seen[j] && m.data[j] == val && m.samePlacement(i, j) && (val != 'O' || m.label(j) == "") {
+					seen[j] = true
+					r.cells = append(r.cells, j)
+				}
+			}
+		}
+		res = append(res, r)
+	}
+	return res
+}
+
+// loops returns the outlines of the region, the corners in cell units in
+// the clockwise order, with the region on the right of each side.
+func (r region) loops(width int) [][]Point {
+	in := make(map[Point]bool, len(r.cells))
+	for _, c := range r.cells {
+		in[Point{c % width, c / width}] = true
+	}
+
+	// The sides of the cells on the border of the region, by their start.
+	next := map[Point][]Point{}
+	for p := range in {
+		if !in[Point{p.X, p.Y - 1}] {
+			next[p] = append(next[p], Point{p.X + 1, p.Y})
+		}
+		if !in[Point{p.X + 1, p.Y}] {
+			next[Point{p.X + 1, p.Y}] = append(next[Point{p.X + 1, p.Y}], Point{p.X + 1, p.Y + 1})
+		}
+		if !in[Point{p.X, p.Y + 1}] {
+			next[Point{p.X + 1, p.Y + 1}] = append(next[Point{p.X + 1, p.Y + 1}], Point{p.X, p.Y + 1})
+		}
+		if !in[Point{p.X - 1, p.Y}] {
+			next[Point{p.X, p.Y + 1}] = append(next[Point{p.X, p.Y + 1}], p)
+		}
+	}
+
+	var res [][]Point
+	for len(next) > 0 {
+		// Start on the top left corner, so the loops come in the same order.
+		start := Point{math.MaxInt, math.MaxInt}
+		for p := range next {
+			if p.Y < start.Y || (p.Y == start.Y && p.X < start.X) {
+				start = p
+			}
+		}
+
+		loop := []Point{start}
+		dir := Point{}
+		for p := start; ; {
+			to := next[p]
+			// Two cells of the region touch on the corner, turn right to stay
+			// around the same cell.
+			k := 0
+			for i, t := range to {
+				d := Point{t.X - p.X, t.Y - p.Y}
+				if d.X == -dir.Y && d.Y == dir.X {
+					k = i
+				}
+			}
+			t := to[k]
+			if len(to) == 1 {
+				delete(next, p)
+			} else {
+				next[p] = append(to[:k:k], to[k+1:]...)
+			}
+
+			d := Point{t.X - p.X, t.Y - p.Y}
+			if d == dir {
+				loop[len(loop)-1] = t
+			} else {
+				loop = append(loop, t)
+			}
+			dir, p = d, t
+			if p == start {
+				break
+			}
+		}
+
+		// The last side may go on in the direction of the first one.
+		loop = loop[:len(loop)-1]
+		if len(loop) > 2 {
+			first := Point{loop[1].X - loop[0].X, loop[1].Y - loop[0].Y}
+			last := Point{loop[0].X - loop[len(loop)-1].X, loop[0].Y - loop[len(loop)-1].Y}
+			if sign(first) == sign(last) {
+				loop = loop[1:]
+			}
+		}
+		res = append(res, loop)
+	}
+	return res
+}
+
+// sign returns the direction of the side d.
+func sign(d Point) Point {
+	s := func(v int) int {
+		switch {
+		case v > 0:
+			return 1
+		case v < 0:
+			return -1
+		}
+		return 0
+	}
+	return Point{s(d.X), s(d.Y)}
+}
+
+// pather draws the paths, like vector.Rasterizer.
+type pather interface {
+	MoveTo(x, y float32)
+	LineTo(x, y float32)
+	QuadTo(bx, by, cx, cy float32)
+	ClosePath()
+}
+
+// tracePath draws the loops, in cells of the size, moved inside the region by
+// inset and with the corners rounded by radius, in pixels.
+func tracePath(p pather, loops [][]Point, cell, inset, radius float32) {
+	for _, loop := range loops {
+		n := len(loop)
+		corners := make([][2]float32, n)
+		for i, c := range loop {
+			in := sign(Point{c.X - loop[(i+n-1)%n].X, c.Y - loop[(i+n-1)%n].Y})
+			out := sign(Point{loop[(i+1)%n].X - c.X, loop[(i+1)%n].Y - c.Y})
+			// The region is on the right of the sides.
+			corners[i] = [2]float32{
+				float32(c.X)*cell + inset*float32(-in.Y-out.Y),
+				float32(c.Y)*cell + inset*float32(in.X+out.X),
+			}
+		}
+
+		for i, c := range corners {
+			prev, next := corners[(i+n-1)%n], corners[(i+1)%n]
+			r := min(radius, length(prev, c)/2, length(c, next)/2)
+			a := towards(c, prev, r)
+			b := towards(c, next, r)
+			if i == 0 {
+				p.MoveTo(a[0], a[1])
+			} else {
+				p.LineTo(a[0], a[1])
+			}
+			if r > 0 {
+				p.QuadTo(c[0], c[1], b[0], b[1])
+			}
+		}
+		p.ClosePath()
+	}
+}
+
+func length(a, b [2]float32) float32 {
+	return float32(math.Hypot(float64(b[0]-a[0]), float64(b[1]-a[1])))
+}
+
+// towards returns the point at the distance d from a towards b.
+func towards(a, b [2]float32, d float32) [2]float32 {
+	l := length(a, b)
+	if l == 0 {
+		return a
+	}
+	return [2]float32{a[0] + (b[0]-a[0])*d/l, a[1] + (b[1]-a[1])*d/l}
+}
+
+// svgPath is the d attribute of an SVG path.
+type svgPath struct {
+	strings.Builder
+}
+
+func (s *svgPath) MoveTo(x, y float32) {
+	fmt.Fprintf(s, "M%g %g", x, y)
+}
+
+func (s *svgPath) LineTo(x, y float32) {
+	fmt.Fprintf(s, "L%g %g", x, y)
+}
+
+func (s *svgPath) QuadTo(bx, by, cx, cy float32) {
+	fmt.Fprintf(s, "Q%g %g %g %g", bx, by, cx, cy)
+}
+
+func (s *svgPath) ClosePath() {
+	s.WriteString("Z")
+}
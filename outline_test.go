@@ -0,0 +1,94 @@
+package psolver
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestRegions(t *testing.T) {
+	m := NewMatrix(3, 3)
+	copy(m.data, "FFIFOI\x00\x00I")
+	m.Labels = make([]string, 9)
+	m.Labels[6] = "D1"
+
+	got := map[byte]int{}
+	for _, r := range regions(m) {
+		got[r.val] += len(r.cells)
+	}
+	want := map[byte]int{'F': 3, 'I': 3, 'O': 1, 0: 1}
+	for val, n := range want {
+		if got[val] != n {
+			t.Errorf("Expected %d cells of %q, got %d", n, val, got[val])
+		}
+	}
+}
+
+func TestRegionsCopies(t *testing.T) {
+	bag, err := ParseBag("L*2")
+	if err != nil {
+		t.Fatalf("ParseBag failed: %v", err)
+	}
+	sol := <-SolveBag(context.Background(), NewMatrix(5, 2), bag, SolveOptions{Engine: EngineAuto, Limit: 1})
+	if sol == nil {
+		t.Fatal("Expected a solution")
+	}
+	imported, err := (&StringImporter{Bag: bag}).Import(strings.NewReader(sol.String()))
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+
+	for name, m := range map[string]*Matrix{"solved": sol, "imported": imported} {
+		rs := regions(m)
+		if len(rs) != 2 || len(rs[0].cells) != 5 || len(rs[1].cells) != 5 {
+			t.Errorf("%s: expected two regions of the L, got %v", name, rs)
+		}
+		if steps := SolutionSteps(NewMatrix(5, 2), m); len(steps) != 2 {
+			t.Errorf("%s: expected two steps, got %v", name, steps)
+		}
+	}
+}
+
+func TestRegionLoops(t *testing.T) {
+	tests := []struct {
+		name    string
+		cells   []int
+		corners []int
+	}{
+		{"square", []int{0, 1, 3, 4}, []int{4}},
+		{"L", []int{0, 3, 6, 7}, []int{6}},
+		// A ring has the outline of the hole too.
+		{"ring", []int{0, 1, 2, 3, 5, 6, 7, 8}, []int{4, 4}},
+		// The cells 5 and 7 touch on the corner, the outline goes around the
+		// hole and touches itself there.
+		{"pinch", []int{0, 1, 2, 3, 5, 6, 7}, []int{10}},
+	}
+	for _, tt := range tests {
+		loops := region{val: 'X', cells: tt.cells}.loops(3)
+		if len(loops) != len(tt.corners) {
+			t.Errorf("%s: expected %d loops, got %v", tt.name, len(tt.corners), loops)
+			continue
+		}
+		for i, loop := range loops {
+			if len(loop) != tt.corners[i] {
+				t.Errorf("%s: expected %d corners, got %v", tt.name, tt.corners[i], loop)
+			}
+		}
+	}
+}
+
+func TestTracePath(t *testing.T) {
+	loops := region{val: 'X', cells: []int{0}}.loops(1)
+
+	p := &svgPath{}
+	tracePath(p, loops, 10, 0, 0)
+	if p.String() != "M0 0L10 0L10 10L0 10Z" {
+		t.Errorf("Unexpected path %q", p.String())
+	}
+
+	p = &svgPath{}
+	tracePath(p, loops, 10, 1, 2)
+	if p.String() != "M1 3Q1 1 3 1L7 1Q9 1 9 3L9 7Q9 9 7 9L3 9Q1 9 1 7Z" {
+		t.Errorf("Unexpected path %q", p.String())
+	}
+}
@@ -0,0 +1,59 @@
+package psolver
+
+import (
+	"context"
+	"slices"
+)
+
+// EmptyCells returns the number of cells not blocked or covered by a piece.
+func (m *Matrix) EmptyCells() int {
+	n := 0
+	for _, c := range m.data {
+		if c == 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// Remaining returns the pieces with no cell on the board.
+func Remaining(m *Matrix, pieces []Piece) []Piece {
+	var res []Piece
+	for _, p := range pieces {
+		if !slices.Contains(m.data, p.Name()) {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+// Remaining returns the copies of the bag not on the board, like the one read
+// by StringImporter with the bag. The copies on the board are told by the
+// number of the cells with the name of the piece.
+func (b Bag) Remaining(m *Matrix) Bag {
+	var res Bag
+	for _, item := range b {
+		points, err := item.Piece.Position(Point{}, 0)
+		if err != nil {
+			continue
+		}
+		placed := 0
+		for _, c := range m.data {
+			if c == item.Piece.Name() {
+				placed++
+			}
+		}
+		if left := max(item.Count, 1) - placed/len(points); left > 0 {
+			item.Count = left
+			res = append(res, item)
+		}
+	}
+	return res
+}
+
+// SolvePartial solves a board with some of the pieces already placed, like
+// the one read by ParseMatrix, with the remaining pieces. The placed pieces
+// stay in place in the solutions.
+func SolvePartial(ctx context.Context, m *Matrix, pieces []Piece, opts SolveOptions) <-chan *Matrix {
+	return SolveContext(ctx, m, Remaining(m, pieces), opts)
+}
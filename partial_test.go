@@ -0,0 +1,33 @@
+package psolver
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestSolvePartial(t *testing.T) {
+	m, err := ParseMatrix(strings.NewReader("UU...\nU....\nUU...\n"))
+	if err != nil {
+		t.Fatalf("ParseMatrix failed: %v", err)
+	}
+	if m.EmptyCells() != 10 {
+		t.Errorf("Expected 10 empty cells, got %d", m.EmptyCells())
+	}
+
+	pieces := smallSet(t, PieceP, PieceU, PieceV)
+	if n := len(Remaining(m, pieces)); n != 2 {
+		t.Errorf("Expected 2 remaining pieces, got %d", n)
+	}
+
+	n := 0
+	for sol := range SolvePartial(context.Background(), m, pieces, SolveOptions{Engine: EngineAuto}) {
+		if sol.data[0] != 'U' || sol.data[5] != 'U' || sol.data[10] != 'U' {
+			t.Errorf("The placed piece is moved\n%s", sol)
+		}
+		n++
+	}
+	if n != 2 {
+		t.Errorf("Expected 2 solutions, got %d", n)
+	}
+}
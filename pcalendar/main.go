@@ -1,35 +1,100 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	psolver "github.com/fzerorubigd/pentomino-solver"
-	"github.com/mshafiee/jalali"
 )
 
-func getTomorrow(j bool) (int, int, int, int) {
-	date := time.Now().AddDate(0, 0, 1)
-	if j {
-		jDate := jalali.ToJalali(date)
+// dateCalendar is a calendar that also takes the date as numbers.
+type dateCalendar interface {
+	psolver.Calendar
+	SetDate(WD, D, M, Y int) error
+	SetLocale(l *psolver.Locale)
+}
+
+func loadBoard(name string) (*psolver.Board, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return psolver.LoadBoard(f)
+}
 
-		wd := int(jDate.Weekday()) + 2
-		if wd > 7 {
-			wd -= 7
+// export writes the board to name with the extension in dir, or to the
+// standard output when there is no extension.
+func export(exporter psolver.Exporter, r *psolver.Matrix, dir, name, ext string) {
+	if ext == "" {
+		fmt.Println(name, "===>")
+		if err := exporter.Export(r, os.Stdout); err != nil {
+			fmt.Println("Error exporting:", err)
 		}
-		return wd, jDate.Day(), int(jDate.Month()), jDate.Year() - 1403
+		return
+	}
+
+	fileName := filepath.Join(dir, name+ext)
+	f, err := os.Create(fileName)
+	if err != nil {
+		fmt.Printf("Error creating file %s: %v\n", fileName, err)
+		return
+	}
+	defer f.Close()
+
+	if err := exporter.Export(r, f); err != nil {
+		fmt.Printf("Error exporting to %s: %v\n", fileName, err)
+	}
+	fmt.Printf("Exported %s\n", fileName)
+}
+
+// stepsExporter animates the steps of the search on the board.
+type stepsExporter struct {
+	*psolver.AnimationExporter
+	steps []psolver.Step
+}
+
+func (s *stepsExporter) Export(m *psolver.Matrix, w io.Writer) error {
+	return s.ExportSteps(m, s.steps, w)
+}
+
+// loadTheme returns the built-in theme with the name, or reads the theme from
+// the JSON file.
+func loadTheme(name string) (*psolver.Theme, error) {
+	if t, err := psolver.ParseTheme(name); err == nil {
+		return t, nil
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return psolver.LoadTheme(f)
+}
+
+func loadMatrix(name string, pieces []psolver.Piece) (*psolver.Matrix, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
 	}
-	return int(date.Weekday()) + 1, date.Day(), int(date.Month()), date.Year() - 2024
+	defer f.Close()
 
+	im := &psolver.StringImporter{Pieces: pieces}
+	return im.Import(f)
 }
 
 func main() {
-	var W, D, M, Y, count int
-	var color, svg, png, tomorrow, jalaliDate bool
-	var outputDir string
+	var W, D, M, Y, count, hint, cellSize, radius, gap, searchSteps int
+	var color, svg, png, gif, apng, tomorrow, jalaliDate, countOnly, oneSided, validate bool
+	var outputDir, engineName, boardName, boardFile, startFile, timezone, localeName, fontFile, footerAlign, themeName string
 	flag.IntVar(&W, "weekday", 1, "The weekday, 1 for the first day, 7 for 7th day. (Shanbe is first for Persian, Monday for Gregorian)")
 	flag.IntVar(&D, "day", 1, "The day of the month, 1 to 31")
 	flag.IntVar(&M, "month", 1, "The month, 1 to 12")
@@ -38,35 +103,221 @@ func main() {
 	flag.BoolVar(&color, "color", true, "Use color output")
 	flag.BoolVar(&svg, "svg", false, "Output SVG files (1.svg, 2.svg, ...)")
 	flag.BoolVar(&png, "png", false, "Output PNG files (1.png, 2.png, ...)")
+	flag.BoolVar(&gif, "gif", false, "Output animated GIF files of the pieces placed one by one (1.gif, 2.gif, ...)")
+	flag.BoolVar(&apng, "apng", false, "Output animated PNG files of the pieces placed one by one (1.png, 2.png, ...)")
+	flag.IntVar(&searchSteps, "search-steps", 0, "With -gif or -apng, only animate this many steps of the search for the first solution, placing and removing the pieces (search.gif or search.png)")
 	flag.StringVar(&outputDir, "output-dir", "", "Output directory for SVG/PNG files")
 	flag.BoolVar(&tomorrow, "tomorrow", false, "Output tomorrow's calendar, ignore all other date related flags")
+	flag.StringVar(&localeName, "locale", "en", "The language of the cell labels and the footer, en or fa")
+	flag.StringVar(&fontFile, "font", "", "A TrueType or OpenType font for the PNG files, needed for the Persian script of -locale fa")
+	flag.IntVar(&cellSize, "cell-size", 32, "The size of the cells of the SVG/PNG files in pixels, the text grows with it")
+	flag.IntVar(&radius, "radius", 0, "Round the corners of the pieces in the SVG/PNG files by this many pixels")
+	flag.IntVar(&gap, "gap", 0, "Leave this many pixels between the pieces in the SVG/PNG files")
+	flag.StringVar(&footerAlign, "footer-align", "start", "The alignment of the footer of the SVG/PNG files, start, center or end")
+	flag.StringVar(&timezone, "timezone", "", "The time zone of tomorrow, like Asia/Tehran, empty for the local time zone")
 
 	flag.BoolVar(&jalaliDate, "jalali", false, "Use jalali calendar")
+	flag.StringVar(&engineName, "engine", "auto", "The solver engine, auto, backtrack, bitboard or dlx")
+	flag.BoolVar(&countOnly, "count-only", false, "Only count the solutions, per worker and in total")
+	flag.IntVar(&hint, "hint", 0, "Show only a hint with this many pieces of a solution placed, the pieces forced in every solution first (hint.svg or hint.png for images)")
+	flag.BoolVar(&oneSided, "one-sided", false, "Use only the rotations of the pieces, without flipping them over")
+	flag.StringVar(&boardName, "board", "", "The built-in calendar board, one of "+strings.Join(psolver.CalendarBoards(), ", ")+", empty for persian with -jalali and gregorian otherwise")
+	flag.StringVar(&boardFile, "board-file", "", "Load the calendar board from a JSON board definition, with W1..W7, D1..D31, M1..M12 and Y1..Y10 cells")
+	flag.StringVar(&startFile, "start-file", "", "Load a board with the date blocked and some pieces already placed, as printed with -color=false, and solve it with the other pieces of the board, ignore all other date flags")
+	flag.BoolVar(&validate, "validate", false, "Check each solution, exit with an error on the first invalid one")
+	flag.StringVar(&themeName, "theme", "default", "The colors, one of "+strings.Join(psolver.Themes(), ", ")+" or a JSON theme file")
 	flag.Parse()
 
+	engine, err := psolver.ParseEngine(engineName)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(-1)
+	}
+
+	locale, err := psolver.ParseLocale(localeName)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(-1)
+	}
+
+	align, err := psolver.ParseAlign(footerAlign)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(-1)
+	}
+
+	theme, err := loadTheme(themeName)
+	if err != nil {
+		fmt.Printf("Error loading theme %s: %v\n", themeName, err)
+		os.Exit(-1)
+	}
+
 	var exporter psolver.Exporter
 	var ext string
 	if svg {
-		exporter = psolver.NewSVGExporter()
+		e := psolver.NewSVGExporter()
+		e.CellSize = cellSize
+		e.Radius, e.Gap = radius, gap
+		e.Locale = locale
+		e.FooterAlign = align
+		e.Theme = theme
+		exporter = e
 		ext = ".svg"
-	} else if png {
-		exporter = psolver.NewPNGExporter()
+	} else if png || gif || apng {
+		e := psolver.NewPNGExporter()
+		e.CellSize = cellSize
+		e.Radius, e.Gap = radius, gap
+		e.Locale = locale
+		e.FooterAlign = align
+		e.Theme = theme
+		if fontFile != "" {
+			data, err := os.ReadFile(fontFile)
+			if err != nil {
+				fmt.Printf("Error loading font %s: %v\n", fontFile, err)
+				os.Exit(-1)
+			}
+			e.Font, err = psolver.ParseFont(data)
+			if err != nil {
+				fmt.Printf("Error loading font %s: %v\n", fontFile, err)
+				os.Exit(-1)
+			}
+		} else if locale.RTL {
+			fmt.Println("The PNG files need a font with the Persian script, see -font")
+			os.Exit(-1)
+		}
+		exporter = e
 		ext = ".png"
+		if gif || apng {
+			a := psolver.NewAnimationExporter()
+			a.PNG = e
+			a.APNG = apng
+			exporter = a
+			if gif {
+				ext = ".gif"
+			}
+		}
 	} else if color {
-		exporter = psolver.NewColorStringExporter()
+		e := psolver.NewColorStringExporter()
+		e.Theme = theme
+		exporter = e
 	} else {
 		exporter = &psolver.StringExporter{}
 	}
 
+	// The first year for the boards without the year cells, only for the footer.
+	base := 2025
+	if boardName == "" {
+		boardName = "gregorian"
+		if jalaliDate {
+			boardName = "persian"
+		}
+	}
+	if jalaliDate {
+		base = 1404
+	}
+	board, pie, err := psolver.NewCalendarBoard(boardName)
+	if err != nil {
+		fmt.Println("Error loading board:", err)
+		os.Exit(-1)
+	}
+	if boardFile != "" {
+		board, err = loadBoard(boardFile)
+		if err != nil {
+			fmt.Printf("Error loading board %s: %v\n", boardFile, err)
+			os.Exit(-1)
+		}
+		pie = psolver.New12()
+	}
+
+	var cal dateCalendar = psolver.NewGregorianCalendarFrom(board)
+	if jalaliDate {
+		cal = psolver.NewPersianCalendarFrom(board)
+	}
+	cal.SetLocale(locale)
+
 	if tomorrow {
-		W, D, M, Y = getTomorrow(jalaliDate)
+		loc := time.Local
+		if timezone != "" {
+			loc, err = time.LoadLocation(timezone)
+			if err != nil {
+				fmt.Println("Error loading time zone:", err)
+				os.Exit(-1)
+			}
+		}
+		err = cal.SetTime(time.Now().AddDate(0, 0, 1), loc)
+	} else {
+		first, _ := cal.Years()
+		if first == 0 {
+			first = base
+		}
+		err = cal.SetDate(W, D, M, Y+first-1)
+	}
+	if err != nil {
+		fmt.Println("Error setting date:", err)
+		os.Exit(-1)
+	}
+	puzzle := cal.Board()
+	all := pie
+
+	if startFile != "" {
+		var err error
+		puzzle, err = loadMatrix(startFile, pie)
+		if err != nil {
+			fmt.Printf("Error loading board %s: %v\n", startFile, err)
+			os.Exit(-1)
+		}
+		pie = psolver.Remaining(puzzle, pie)
+	}
+
+	opts := psolver.SolveOptions{
+		Engine:   engine,
+		Buffer:   10,
+		OneSided: oneSided,
+	}
+	if err := engine.Check(puzzle); err != nil {
+		fmt.Println(err)
+		os.Exit(-1)
+	}
+
+	if hint > 0 {
+		r, err := psolver.Hint(context.Background(), puzzle, pie, hint, opts)
+		if err != nil {
+			fmt.Println("Error finding hint:", err)
+			os.Exit(-1)
+		}
+		r.Footer = puzzle.Footer
+		export(exporter, r, outputDir, "hint", ext)
+		return
+	}
+
+	if searchSteps > 0 {
+		a, ok := exporter.(*psolver.AnimationExporter)
+		if !ok {
+			fmt.Println("The search steps need -gif or -apng")
+			os.Exit(-1)
+		}
+		steps, err := psolver.Trace(context.Background(), puzzle, pie, searchSteps, opts)
+		if err != nil {
+			fmt.Println("Error tracing the search:", err)
+			os.Exit(-1)
+		}
+		export(&stepsExporter{a, steps}, puzzle, outputDir, "search", ext)
+		return
 	}
 
-	cal := psolver.NewPersianCalendar()
-	pie := psolver.New12()
-	cal.SetDate(W, D, M, Y+1403)
-	resp := make(chan *psolver.Matrix, 10)
-	psolver.Solve(&cal.Matrix, pie, resp)
+	if countOnly {
+		res, err := psolver.CountWithOptions(context.Background(), puzzle, pie, opts)
+		if err != nil {
+			fmt.Println("Error counting:", err)
+			os.Exit(-1)
+		}
+		fmt.Print(res)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	resp := psolver.SolveContext(ctx, puzzle, pie, opts)
 
 	mm := map[string]struct{}{}
 	i := 1
@@ -75,34 +326,18 @@ func main() {
 			continue
 		}
 		mm[r.Hash()] = struct{}{}
-
-		if jalaliDate {
-			r.Footer = fmt.Sprintf("%d-%02d-%02d", Y+1403, M, D)
-		} else {
-			r.Footer = fmt.Sprintf("%d-%02d-%02d", Y+2024, M, D)
-		}
-
-		if svg || png {
-			fileName := filepath.Join(outputDir, fmt.Sprintf("%d%s", i, ext))
-			f, err := os.Create(fileName)
-			if err != nil {
-				fmt.Printf("Error creating file %s: %v\n", fileName, err)
-				continue
-			}
-			if err := exporter.Export(r, f); err != nil {
-				fmt.Printf("Error exporting to %s: %v\n", fileName, err)
-			}
-			f.Close()
-			fmt.Printf("Exported %s\n", fileName)
-		} else {
-			fmt.Println(i, "===>")
-			if err := exporter.Export(r, os.Stdout); err != nil {
-				fmt.Println("Error exporting:", err)
+		if validate {
+			if err := psolver.ValidateBoard(puzzle, r, all); err != nil {
+				fmt.Println("Invalid solution:", err)
+				os.Exit(-1)
 			}
 		}
 
+		r.Footer = puzzle.Footer
+		export(exporter, r, outputDir, fmt.Sprint(i), ext)
+
 		if count > 0 && i == count {
-			os.Exit(0)
+			break
 		}
 		i += 1
 	}
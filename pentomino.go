@@ -1,9 +1,12 @@
 package psolver
 
 import (
+	"context"
 	"crypto/sha1"
 	"fmt"
+	"slices"
 	"sync"
+	"sync/atomic"
 )
 
 type Matrix struct {
@@ -12,7 +15,13 @@ type Matrix struct {
 
 	data   []byte
 	pieces map[byte]int
+	// owner is the first cell plus one of the placement that covers each
+	// cell, nil when the placements are not known.
+	owner  []int
 	Footer string
+	// Labels of the cells, like "14" and "Mar" on a calendar, drawn by the
+	// image exporters on the cells not covered by a piece. Nil for no labels.
+	Labels []string
 }
 
 type Point struct {
@@ -22,7 +31,7 @@ type Point struct {
 type Piece interface {
 	Name() byte
 	States() int
-	Position(ref Point, state int) ([5]Point, error)
+	Position(ref Point, state int) ([]Point, error)
 }
 
 func NewMatrix(w, h int) *Matrix {
@@ -108,21 +117,25 @@ func (m *Matrix) place(p Piece, pos Point, state int) error {
 	}
 	m.pieces[p.Name()] = state
 
+	first := points[0].Y*m.Width + points[0].X
 	for _, pt := range points {
 		m.data[pt.Y*m.Width+pt.X] = p.Name()
+		m.own(pt.Y*m.Width+pt.X, first)
 	}
 
 	return nil
 }
 
-func (m *Matrix) remove(p Piece) {
-	name := p.Name()
-	for j := 0; j < m.Height; j++ {
-		for i := 0; i < m.Width; i++ {
-			if m.data[j*m.Width+i] == name {
-				m.data[j*m.Width+i] = 0
-			}
-		}
+// unplace empties the cells of a piece placed by place.
+func (m *Matrix) unplace(p Piece, pos Point, state int) {
+	points, err := p.Position(pos, state)
+	if err != nil {
+		return
+	}
+
+	for _, pt := range points {
+		m.data[pt.Y*m.Width+pt.X] = 0
+		m.own(pt.Y*m.Width+pt.X, -1)
 	}
 }
 
@@ -132,10 +145,36 @@ func (m *Matrix) duplicate() *Matrix {
 	for i := range m.pieces {
 		n.pieces[i] = m.pieces[i]
 	}
+	n.Labels = m.Labels
+	n.owner = slices.Clone(m.owner)
 
 	return n
 }
 
+// own records that the cell idx is covered by the placement starting on the
+// cell first, -1 when it is empty, so the copies of a piece that touch each
+// other are told apart.
+func (m *Matrix) own(idx, first int) {
+	if m.owner == nil {
+		m.owner = make([]int, len(m.data))
+	}
+	m.owner[idx] = first + 1
+}
+
+// samePlacement reports whether the cells i and j are covered by the same
+// placement, always true when the placements are not known.
+func (m *Matrix) samePlacement(i, j int) bool {
+	return m.owner == nil || m.owner[i] == m.owner[j]
+}
+
+// label returns the label of the cell at idx, empty when it has none.
+func (m *Matrix) label(idx int) string {
+	if idx >= len(m.Labels) {
+		return ""
+	}
+	return m.Labels[idx]
+}
+
 func (m *Matrix) Pieces() map[byte]int {
 	return m.pieces
 }
@@ -151,57 +190,339 @@ func Minus(p []Piece, idx int) []Piece {
 	return nP
 }
 
-func SolveSingle(m *Matrix, p []Piece, ans chan *Matrix) {
-	// return condition
-	if len(p) == 0 {
-		if m.isFull() {
-			ans <- m.duplicate()
+// Engine selects the algorithm used to search for the solutions. The zero
+// value picks the engine like EngineAuto.
+type Engine int
+
+const (
+	// EngineBacktrack is the plain backtracking search, filling the first empty cell.
+	EngineBacktrack Engine = iota + 1
+	// EngineDLX is Knuth's Algorithm X using dancing links.
+	EngineDLX
+	// EngineBitboard is the backtracking search over precomputed placement
+	// masks, for boards up to 128 cells.
+	EngineBitboard
+	// EngineAuto picks EngineBitboard when the board is small enough,
+	// EngineBacktrack otherwise.
+	EngineAuto
+)
+
+var engineNames = map[string]Engine{
+	"auto":      EngineAuto,
+	"backtrack": EngineBacktrack,
+	"dlx":       EngineDLX,
+	"bitboard":  EngineBitboard,
+}
+
+// ParseEngine returns the engine with the given name.
+func ParseEngine(name string) (Engine, error) {
+	e, ok := engineNames[name]
+	if !ok {
+		return 0, fmt.Errorf("%q is invalid engine", name)
+	}
+	return e, nil
+}
+
+// String returns the name of the engine, as taken by ParseEngine.
+func (e Engine) String() string {
+	if e == 0 {
+		e = EngineAuto
+	}
+	for name, v := range engineNames {
+		if v == e {
+			return name
+		}
+	}
+	return fmt.Sprintf("Engine(%d)", int(e))
+}
+
+// Check returns an error for an unknown engine, and ErrBoardTooLarge when
+// EngineBitboard can not search the board. SolveContext and SolveBag search
+// such a board with EngineDLX instead.
+func (e Engine) Check(m *Matrix) error {
+	if e < 0 || e > EngineAuto {
+		return fmt.Errorf("%d is invalid engine", int(e))
+	}
+	if e == EngineBitboard && len(m.data) > bitboardCells {
+		return fmt.Errorf("%w: %d cells", ErrBoardTooLarge, len(m.data))
+	}
+	return nil
+}
+
+// SolveOptions controls the search started by SolveContext.
+type SolveOptions struct {
+	// Engine is the search algorithm, the zero value picks it like
+	// EngineAuto.
+	Engine Engine
+	// Limit stops the search after that many solutions, zero means no limit.
+	Limit int
+	// Buffer is the capacity of the returned channel.
+	Buffer int
+	// Unique reports only one solution out of each class of solutions that
+	// are rotations or reflections of each other. Only the symmetries of the
+	// board that keep the blocked cells and the set of pieces in place count,
+	// so a calendar board has no symmetric solutions.
+	Unique bool
+	// OneSided uses only the rotations of the pieces, they can not be flipped
+	// over. See OneSided.
+	OneSided bool
+}
+
+// search holds the state shared by all the workers of a single solve.
+type search struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	ans    chan<- *Matrix // nil when only counting
+	limit  int64
+	found  atomic.Int64
+	sym    *symmetryFilter
+	// workers counts the solutions found by each worker, by its index.
+	workers []atomic.Int64
+
+	// The kinds of pieces, with the least and the most copies of each kind
+	// that a solution uses.
+	pieces []Piece
+	least  []int
+	most   []int
+
+	// trace, when set, is called on each placement and removal of a piece
+	// by backtrack, see Trace.
+	trace func(p Piece, pos Point, state int, removed bool)
+}
+
+func newSearch(ctx context.Context, m *Matrix, bag Bag, opts SolveOptions, ans chan<- *Matrix) *search {
+	ctx, cancel := context.WithCancel(ctx)
+	s := &search{
+		ctx:    ctx,
+		cancel: cancel,
+		ans:    ans,
+		limit:  int64(opts.Limit),
+		// A single worker, until the engine starts its own.
+		workers: make([]atomic.Int64, 1),
+	}
+	s.pieces, s.least, s.most = bag.bounds()
+	if opts.OneSided {
+		for i := range s.pieces {
+			s.pieces[i] = OneSided(s.pieces[i])
+		}
+	}
+	if opts.Unique {
+		s.sym = newSymmetryFilter(m, s.pieces, s.least, s.most)
+	}
+	return s
+}
+
+// placements lists the placements of the pieces allowed in this search.
+func (s *search) placements(m *Matrix) []placement {
+	pls := placements(m, s.pieces)
+	if s.sym != nil {
+		pls = s.sym.filter(pls)
+	}
+	return pls
+}
+
+// allows reports whether the piece may be placed at pos in this search.
+func (s *search) allows(m *Matrix, p Piece, pos Point, state int) bool {
+	if s.sym == nil || p.Name() != s.sym.name {
+		return true
+	}
+	points, _ := p.Position(pos, state)
+	cells := make([]int, 0, len(points))
+	for _, pt := range points {
+		cells = append(cells, pt.Y*m.Width+pt.X)
+	}
+	return s.sym.allows(p.Name(), cells)
+}
+
+// complete reports whether enough copies of each kind are placed, left is the
+// number of copies of each kind not placed yet.
+func (s *search) complete(left []int) bool {
+	for k := range left {
+		if s.most[k]-left[k] < s.least[k] {
+			return false
+		}
+	}
+	return true
+}
+
+// stopped reports whether the search is cancelled, it never blocks.
+func (s *search) stopped() bool {
+	select {
+	case <-s.ctx.Done():
+		return true
+	default:
+		return false
+	}
+}
+
+// emit records a solution found by the worker and sends a copy of it, board
+// is called only when the solution is needed. It reports whether the search
+// should go on.
+func (s *search) emit(worker int, board func() *Matrix) bool {
+	var m *Matrix
+	if s.sym != nil {
+		m = board()
+		if !s.sym.canonical(m) {
+			return true
+		}
+	}
+
+	n := s.found.Add(1)
+	if s.limit > 0 && n > s.limit {
+		return false
+	}
+	s.workers[worker].Add(1)
+
+	if s.ans != nil {
+		if m == nil {
+			m = board()
+		}
+		select {
+		case s.ans <- m.duplicate():
+		case <-s.ctx.Done():
+			return false
 		}
 	}
 
+	if s.limit > 0 && n == s.limit {
+		s.cancel()
+		return false
+	}
+	return true
+}
+
+// backtrack fills the first empty cell with each kind of piece that has
+// copies left, and goes on until the board is full.
+func (s *search) backtrack(worker int, m *Matrix, left []int) bool {
+	if s.stopped() {
+		return false
+	}
+
 	empty, has := m.findFirstEmpty()
+	// return condition
 	if !has {
-		return
+		if s.complete(left) {
+			return s.emit(worker, func() *Matrix { return m })
+		}
+		return true
 	}
-	for idx, current := range p {
-		rest := Minus(p, idx)
+
+	for k, current := range s.pieces {
+		if left[k] == 0 {
+			continue
+		}
 		states := current.States()
-		for s := range states {
-			if err := m.place(current, empty, s); err == nil {
-				//fmt.Println(m.String())
-				SolveSingle(m, rest, ans)
-				m.remove(current)
+		for st := range states {
+			if m.canPlace(current, empty, st) && s.allows(m, current, empty, st) {
+				m.place(current, empty, st)
+				if s.trace != nil {
+					s.trace(current, empty, st, false)
+				}
+				left[k]--
+				more := s.backtrack(worker, m, left)
+				left[k]++
+				m.unplace(current, empty, st)
+				if !more {
+					return false
+				}
+				if s.trace != nil {
+					s.trace(current, empty, st, true)
+				}
 			}
 		}
 	}
+	return true
+}
+
+func SolveSingle(m *Matrix, p []Piece, ans chan *Matrix) {
+	s := newSearch(context.Background(), m, NewBag(p...), SolveOptions{}, ans)
+	defer s.cancel()
+	s.backtrack(0, m, slices.Clone(s.most))
+}
+
+// SolveContext starts the search in the background and returns the channel of the
+// solutions. The channel is closed when the search space is exhausted, the limit
+// in opts is reached or ctx is cancelled, whichever comes first. EngineBitboard
+// searches the boards that are too large for it with EngineDLX, see
+// Engine.Check.
+func SolveContext(ctx context.Context, m *Matrix, pieces []Piece, opts SolveOptions) <-chan *Matrix {
+	return SolveBag(ctx, m, NewBag(pieces...), opts)
 }
 
 func Solve(m *Matrix, pieces []Piece, ans chan *Matrix) {
-	wg := sync.WaitGroup{}
-	wg.Add(len(pieces))
-	for i, current := range pieces {
-		rest := Minus(pieces, i)
-		go func(ans chan *Matrix, main Piece, rest []Piece) {
+	solve(context.Background(), m, NewBag(pieces...), SolveOptions{}, ans)
+}
+
+func solve(ctx context.Context, m *Matrix, bag Bag, opts SolveOptions, ans chan *Matrix) {
+	s := newSearch(ctx, m, bag, opts, ans)
+	wg := s.run(m, opts.Engine)
+	go func() {
+		wg.Wait()
+		s.cancel()
+		close(ans)
+	}()
+}
+
+// run starts the workers of the engine, the returned group is done when all
+// of them are finished. EngineBitboard falls back to EngineDLX on the boards
+// that are too large for it.
+func (s *search) run(m *Matrix, engine Engine) *sync.WaitGroup {
+	if engine == 0 || engine == EngineAuto {
+		engine = EngineBacktrack
+		if len(m.data) <= bitboardCells {
+			engine = EngineBitboard
+		}
+	}
+	if engine == EngineBitboard && len(m.data) > bitboardCells {
+		engine = EngineDLX
+	}
+
+	wg := &sync.WaitGroup{}
+	switch engine {
+	case EngineDLX:
+		s.dlx(wg, m)
+	case EngineBitboard:
+		s.bitboard(wg, m)
+	default:
+		s.parallel(wg, m)
+	}
+	return wg
+}
+
+// parallel runs the backtracking search with one worker per kind of piece
+// placed on the first empty cell.
+func (s *search) parallel(wg *sync.WaitGroup, m *Matrix) {
+	start, has := m.findFirstEmpty()
+	if !has {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.backtrack(0, m.duplicate(), slices.Clone(s.most))
+		}()
+		return
+	}
+
+	s.workers = make([]atomic.Int64, len(s.pieces))
+	wg.Add(len(s.pieces))
+	for k, current := range s.pieces {
+		go func(k int, main Piece) {
 			defer func() {
 				wg.Done()
 			}()
 			fs := m.duplicate()
-			start, _ := fs.findFirstEmpty()
+			left := slices.Clone(s.most)
+			left[k]--
 			states := main.States()
-			for s := range states {
-				if fs.canPlace(main, start, s) {
-					fs.place(main, start, s)
-					SolveSingle(fs, rest, ans)
-					fs.remove(main)
+			for st := range states {
+				if fs.canPlace(main, start, st) && s.allows(fs, main, start, st) {
+					fs.place(main, start, st)
+					more := s.backtrack(k, fs, left)
+					fs.unplace(main, start, st)
+					if !more {
+						return
+					}
 				}
 			}
-
-		}(ans, current, rest)
-
+		}(k, current)
 	}
-
-	go func() {
-		wg.Wait()
-		close(ans)
-	}()
 }
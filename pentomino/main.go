@@ -1,47 +1,172 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 
 	psolver "github.com/fzerorubigd/pentomino-solver"
 )
 
+func loadBoard(name string) (*psolver.Board, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return psolver.LoadBoard(f)
+}
+
+// loadTheme returns the built-in theme with the name, or reads the theme from
+// the JSON file.
+func loadTheme(name string) (*psolver.Theme, error) {
+	if t, err := psolver.ParseTheme(name); err == nil {
+		return t, nil
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return psolver.LoadTheme(f)
+}
+
+func loadMatrix(name string, bag psolver.Bag) (*psolver.Matrix, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	im := &psolver.StringImporter{Bag: bag}
+	return im.Import(f)
+}
+
 func main() {
 	var w, h, count int
-	var color bool
+	var color, unique, countOnly, oneSided, oneSided18, validate bool
+	var engineName, boardFile, bagSpec, startFile, themeName string
 	flag.IntVar(&count, "count", -1, "The count of the solution to show before exit, -1 to show all")
 	flag.IntVar(&w, "width", 10, "Width of the puzzle")
 	flag.IntVar(&h, "height", 6, "Width of the puzzle")
 	flag.BoolVar(&color, "color", true, "Use color output")
+	flag.BoolVar(&unique, "unique", false, "Show only one solution out of its rotations and reflections")
+	flag.StringVar(&engineName, "engine", "auto", "The solver engine, auto, backtrack, bitboard or dlx")
+	flag.BoolVar(&countOnly, "count-only", false, "Only count the solutions, per worker and in total")
+	flag.StringVar(&boardFile, "board-file", "", "Load the board from a JSON board definition, ignore width and height")
+	flag.BoolVar(&oneSided, "one-sided", false, "Use only the rotations of the pieces, without flipping them over")
+	flag.BoolVar(&oneSided18, "one-sided18", false, "Use the 18 one-sided pentominoes, the 12 pentominoes and their mirror images f, j, n, p, y and z that can not be flipped, in place of -pieces")
+	flag.StringVar(&bagSpec, "pieces", "", "The pieces to use, like L*2,I*3,o? for two L, three I and an optional square tetromino, default is the 12 pentominoes")
+	flag.StringVar(&startFile, "start-file", "", "Load a board with some pieces already placed, as printed with -color=false, and solve it with the other pieces, ignore width, height and board-file")
+	flag.BoolVar(&validate, "validate", false, "Check each solution, exit with an error on the first invalid one")
+	flag.StringVar(&themeName, "theme", "default", "The colors, one of "+strings.Join(psolver.Themes(), ", ")+" or a JSON theme file")
 	flag.Parse()
-	if w*h != 60 {
-		fmt.Println("The size should be 60")
+
+	bag := psolver.NewBag(psolver.New12()...)
+	if oneSided18 {
+		if bagSpec != "" {
+			fmt.Println("-one-sided18 and -pieces can not be used together")
+			os.Exit(-1)
+		}
+		bag = psolver.NewBag(psolver.NewOneSided18()...)
+	} else if bagSpec != "" {
+		var err error
+		bag, err = psolver.ParseBag(bagSpec)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(-1)
+		}
+	}
+
+	puzzle := psolver.NewMatrix(w, h)
+	all := bag
+	if startFile != "" {
+		var err error
+		puzzle, err = loadMatrix(startFile, bag)
+		if err != nil {
+			fmt.Printf("Error loading board %s: %v\n", startFile, err)
+			os.Exit(-1)
+		}
+		bag = bag.Remaining(puzzle)
+	} else if boardFile != "" {
+		board, err := loadBoard(boardFile)
+		if err != nil {
+			fmt.Printf("Error loading board %s: %v\n", boardFile, err)
+			os.Exit(-1)
+		}
+		puzzle = board.Matrix()
+	}
+	if lo, hi := bag.Area(); puzzle.EmptyCells() < lo || puzzle.EmptyCells() > hi {
+		if lo == hi {
+			fmt.Printf("The size should be %d\n", lo)
+		} else {
+			fmt.Printf("The size should be between %d and %d\n", lo, hi)
+		}
+		os.Exit(-1)
+	}
+
+	engine, err := psolver.ParseEngine(engineName)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(-1)
+	}
+
+	theme, err := loadTheme(themeName)
+	if err != nil {
+		fmt.Printf("Error loading theme %s: %v\n", themeName, err)
 		os.Exit(-1)
 	}
 
 	var exporter psolver.Exporter
 	if color {
-		exporter = psolver.NewColorStringExporter()
+		e := psolver.NewColorStringExporter()
+		e.Theme = theme
+		exporter = e
 	} else {
 		exporter = &psolver.StringExporter{}
 	}
 
-	puzzle := psolver.NewMatrix(w, h)
-	pie := psolver.New12()
-	resp := make(chan *psolver.Matrix, 10)
-	psolver.Solve(puzzle, pie, resp)
+	opts := psolver.SolveOptions{
+		Engine:   engine,
+		Limit:    count,
+		Buffer:   10,
+		Unique:   unique,
+		OneSided: oneSided,
+	}
+
+	if err := engine.Check(puzzle); err != nil {
+		fmt.Println(err)
+		os.Exit(-1)
+	}
+
+	if countOnly {
+		res, err := psolver.CountBag(context.Background(), puzzle, bag, opts)
+		if err != nil {
+			fmt.Println("Error counting:", err)
+			os.Exit(-1)
+		}
+		fmt.Print(res)
+		return
+	}
+
+	resp := psolver.SolveBag(context.Background(), puzzle, bag, opts)
 
 	i := 1
 	for r := range resp {
+		if validate {
+			if err := psolver.ValidateBag(puzzle, r, all); err != nil {
+				fmt.Println("Invalid solution:", err)
+				os.Exit(-1)
+			}
+		}
 		fmt.Println(i, "===>")
 		if err := exporter.Export(r, os.Stdout); err != nil {
 			fmt.Println("Error exporting:", err)
 		}
-		if count > 0 && i == count {
-			os.Exit(0)
-		}
 		i += 1
 	}
 }
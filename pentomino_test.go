@@ -0,0 +1,203 @@
+package psolver
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func smallSet(t *testing.T, names ...NamedPiece) []Piece {
+	t.Helper()
+	res := make([]Piece, 0, len(names))
+	for _, n := range names {
+		p, err := NewNamePiece(n)
+		if err != nil {
+			t.Fatalf("NewNamePiece failed: %v", err)
+		}
+		res = append(res, p)
+	}
+	return res
+}
+
+func TestSolveContext(t *testing.T) {
+	pieces := smallSet(t, PieceP, PieceU, PieceV)
+
+	n := 0
+	for range SolveContext(context.Background(), NewMatrix(5, 3), pieces, SolveOptions{}) {
+		n++
+	}
+	if n != 4 {
+		t.Errorf("Expected 4 solutions, got %d", n)
+	}
+}
+
+func TestSolveContextLimit(t *testing.T) {
+	pieces := smallSet(t, PieceP, PieceU, PieceV)
+
+	n := 0
+	for range SolveContext(context.Background(), NewMatrix(5, 3), pieces, SolveOptions{Limit: 2}) {
+		n++
+	}
+	if n != 2 {
+		t.Errorf("Expected 2 solutions, got %d", n)
+	}
+}
+
+func TestSolveContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// The full 6x10 board takes minutes, a cancelled search must return at once
+	n := 0
+	for range SolveContext(ctx, NewMatrix(10, 6), New12(), SolveOptions{}) {
+		n++
+	}
+	if n != 0 {
+		t.Errorf("Expected no solution after cancel, got %d", n)
+	}
+}
+
+func TestSolveEngines(t *testing.T) {
+	cal := NewPersianCalendar()
+	if err := cal.SetDate(1, 1, 1, 1404); err != nil {
+		t.Fatalf("SetDate failed: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		m       *Matrix
+		pieces  []Piece
+		engines []Engine
+		want    int
+	}{
+		{
+			name:    "5x3",
+			m:       NewMatrix(5, 3),
+			pieces:  smallSet(t, PieceP, PieceU, PieceV),
+			engines: []Engine{EngineBacktrack, EngineDLX, EngineBitboard},
+			want:    4,
+		},
+		{
+			name:    "20x3",
+			m:       NewMatrix(20, 3),
+			pieces:  New12(),
+			engines: []Engine{EngineDLX, EngineBitboard},
+			want:    8,
+		},
+		{
+			name:    "calendar",
+			m:       &cal.Matrix,
+			pieces:  New12(),
+			engines: []Engine{EngineBitboard},
+			want:    1210,
+		},
+	}
+
+	for _, tt := range tests {
+		for _, e := range tt.engines {
+			t.Run(tt.name+"/"+e.String(), func(t *testing.T) {
+				n := 0
+				for r := range SolveContext(context.Background(), tt.m, tt.pieces, SolveOptions{Engine: e}) {
+					if !r.isFull() {
+						t.Errorf("Solution is not full:\n%s", r)
+					}
+					if err := ValidateBoard(tt.m, r, tt.pieces); err != nil {
+						t.Errorf("Invalid solution: %v\n%s", err, r)
+					}
+					if len(r.Pieces()) != len(tt.pieces) {
+						t.Errorf("Expected %d pieces, got %d", len(tt.pieces), len(r.Pieces()))
+					}
+					n++
+				}
+				if n != tt.want {
+					t.Errorf("Expected %d solutions, got %d", tt.want, n)
+				}
+			})
+		}
+	}
+}
+
+func TestSolveUnique(t *testing.T) {
+	square := NewMatrix(8, 8)
+	for _, c := range []int{27, 28, 35, 36} {
+		square.data[c] = 'O'
+	}
+
+	tests := []struct {
+		name    string
+		m       *Matrix
+		pieces  []Piece
+		engines []Engine
+		want    int
+	}{
+		{
+			name:    "5x3",
+			m:       NewMatrix(5, 3),
+			pieces:  smallSet(t, PieceP, PieceU, PieceV),
+			engines: []Engine{EngineBacktrack, EngineDLX, EngineBitboard},
+			want:    1,
+		},
+		{
+			name:    "20x3",
+			m:       NewMatrix(20, 3),
+			pieces:  New12(),
+			engines: []Engine{EngineDLX, EngineBitboard},
+			want:    2,
+		},
+		{
+			name:    "8x8",
+			m:       square,
+			pieces:  New12(),
+			engines: []Engine{EngineBitboard},
+			want:    65,
+		},
+	}
+
+	for _, tt := range tests {
+		for _, e := range tt.engines {
+			t.Run(tt.name+"/"+e.String(), func(t *testing.T) {
+				n := 0
+				for range SolveContext(context.Background(), tt.m, tt.pieces, SolveOptions{Engine: e, Unique: true}) {
+					n++
+				}
+				if n != tt.want {
+					t.Errorf("Expected %d solutions, got %d", tt.want, n)
+				}
+			})
+		}
+	}
+}
+
+func TestEngineCheck(t *testing.T) {
+	m := NewMatrix(12, 11)
+	if err := EngineBitboard.Check(m); !errors.Is(err, ErrBoardTooLarge) {
+		t.Errorf("Expected ErrBoardTooLarge, got %v", err)
+	}
+	if _, err := CountWithOptions(context.Background(), m, New12(), SolveOptions{Engine: EngineBitboard}); !errors.Is(err, ErrBoardTooLarge) {
+		t.Errorf("Expected ErrBoardTooLarge from Count, got %v", err)
+	}
+	for _, e := range []Engine{0, EngineAuto, EngineBacktrack, EngineDLX} {
+		if err := e.Check(m); err != nil {
+			t.Errorf("Engine %s: unexpected error %v", e, err)
+		}
+	}
+	for _, e := range []Engine{-1, EngineAuto + 1} {
+		if err := e.Check(m); err == nil {
+			t.Errorf("Expected error for %s", e)
+		}
+	}
+	if Engine(0).String() != "auto" {
+		t.Errorf("Expected auto as the zero value, got %s", Engine(0))
+	}
+
+	// The bitboard search falls back to DLX, a 10x6 box on a larger board.
+	m = NewMatrix(13, 10)
+	for i := range m.data {
+		if i%m.Width >= 10 || i/m.Width >= 6 {
+			m.data[i] = 'O'
+		}
+	}
+	if _, ok := <-SolveContext(context.Background(), m, New12(), SolveOptions{Engine: EngineBitboard, Limit: 1}); !ok {
+		t.Error("Expected a solution with EngineBitboard on a large board")
+	}
+}
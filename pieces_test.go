@@ -0,0 +1,99 @@
+package psolver
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+// states returns the normalised states of the piece, with the first point
+// checked to be the first cell in scan order.
+func states(t *testing.T, p Piece) map[string]struct{} {
+	t.Helper()
+	res := map[string]struct{}{}
+	for st := range p.States() {
+		points, err := p.Position(Point{X: 10, Y: 10}, st)
+		if err != nil {
+			t.Fatalf("%s: state %d failed: %v", string(p.Name()), st, err)
+		}
+		for _, pt := range points {
+			if pt.Y < points[0].Y || (pt.Y == points[0].Y && pt.X < points[0].X) {
+				t.Errorf("%s: state %d does not start with its first cell", string(p.Name()), st)
+			}
+		}
+		key := shapeKey(points)
+		if _, ok := res[key]; ok {
+			t.Errorf("%s: state %d is a duplicate", string(p.Name()), st)
+		}
+		res[key] = struct{}{}
+	}
+	return res
+}
+
+func TestGeneratedStates(t *testing.T) {
+	// The fixed pentominoes, the pieces in all of their orientations, in the
+	// order of their state indexes.
+	want := map[byte][]string{
+		'I': {"IIIII", "I/I/I/I/I"},
+		'L': {"L.../LLLL", "LL/L./L./L.", "LLLL/...L", ".L/.L/.L/LL", "L./L./L./LL", "LLLL/L...", "LL/.L/.L/.L", "...L/LLLL"},
+		'P': {"PP/PP/P.", "PP./PPP", ".P/PP/PP", "PPP/.PP", "PP/PP/.P", ".PP/PPP", "P./PP/PP", "PPP/PP."},
+		'T': {"TTT/.T./.T.", "..T/TTT/..T", ".T./.T./TTT", "T../TTT/T.."},
+		'U': {"U.U/UUU", "UU/U./UU", "UUU/U.U", "UU/.U/UU"},
+		'V': {"V../V../VVV", "VVV/V../V..", "VVV/..V/..V", "..V/..V/VVV"},
+		'W': {"WW./.WW/..W", "..W/.WW/WW.", "W../WW./.WW", ".WW/WW./W.."},
+		'X': {".X./XXX/.X."},
+		'Y': {".Y../YYYY", "Y./YY/Y./Y.", "YYYY/..Y.", ".Y/.Y/YY/.Y", "..Y./YYYY", ".Y/YY/.Y/.Y", "YYYY/.Y..", "Y./Y./YY/Y."},
+		'Z': {"ZZ./.Z./.ZZ", "..Z/ZZZ/Z..", ".ZZ/.Z./ZZ.", "Z../ZZZ/..Z"},
+		'F': {".FF/FF./.F.", ".F./FFF/..F", ".F./.FF/FF.", "F../FFF/.F.", "FF./.FF/.F.", "..F/FFF/.F.", ".F./FF./.FF", ".F./FFF/F.."},
+		'N': {"..NN/NNN.", "N./NN/.N/.N", ".NNN/NN..", "N./N./NN/.N", "NN../.NNN", ".N/.N/NN/N.", "NNN./..NN", ".N/NN/N./N."},
+	}
+	all := map[string]struct{}{}
+	for _, p := range New12() {
+		st := states(t, p)
+		if len(st) != len(want[p.Name()]) {
+			t.Errorf("%s: expected %d states, got %d", string(p.Name()), len(want[p.Name()]), len(st))
+		}
+		for i, shape := range want[p.Name()] {
+			got, _ := p.Position(Point{}, i)
+			expected, _ := mustShape(p.Name(), shape).Position(Point{}, 0)
+			if !slices.Equal(got, expected) {
+				t.Errorf("%s: expected state %d %q, got %v", string(p.Name()), i, shape, got)
+			}
+		}
+		maps.Copy(all, st)
+	}
+	if len(all) != 63 {
+		t.Errorf("Expected the 63 fixed pentominoes, got %d", len(all))
+	}
+
+	// The first state is the shape as drawn, OneSided keeps its rotations.
+	l, err := NewNamePiece(PieceL)
+	if err != nil {
+		t.Fatalf("NewNamePiece failed: %v", err)
+	}
+	points, _ := l.Position(Point{}, 0)
+	if shapeKey(points) != shapeKey([]Point{{0, 0}, {0, 1}, {1, 1}, {2, 1}, {3, 1}}) {
+		t.Errorf("Unexpected first state of L %v", points)
+	}
+	if _, err := NewNamePiece("Q"); err == nil {
+		t.Error("Expected error for an unknown name")
+	}
+}
+
+func TestNewShape(t *testing.T) {
+	p, err := NewShape('A', "A A\nA .")
+	if err != nil {
+		t.Fatalf("NewShape failed: %v", err)
+	}
+	if p.Size() != 3 || p.States() != 4 {
+		t.Errorf("Expected 3 cells in 4 states, got %d cells in %d states", p.Size(), p.States())
+	}
+
+	if _, err := NewShape('A', "A . A"); err == nil {
+		t.Error("Expected error for a disconnected shape")
+	}
+	// The empty row between the cells is kept.
+	if _, err := NewShape('A', "A\n\nA"); err == nil {
+		t.Error("Expected error for the rows split by an empty row")
+	}
+}
@@ -0,0 +1,64 @@
+package psolver
+
+import "sort"
+
+// placement is a single way to put a piece on the empty cells of a board.
+type placement struct {
+	piece int // index of the piece in the solved set
+	state int
+	cells []int // indexes into Matrix.data
+}
+
+// placements lists every distinct placement of each piece on the empty cells of m.
+// Placements of the same piece covering the same cells are reported only once.
+func placements(m *Matrix, pieces []Piece) []placement {
+	var res []placement
+	for idx, p := range pieces {
+		seen := map[string]struct{}{}
+		for st := range p.States() {
+			for j := 0; j < m.Height; j++ {
+				for i := 0; i < m.Width; i++ {
+					ref := Point{X: i, Y: j}
+					if !m.canPlace(p, ref, st) {
+						continue
+					}
+					points, _ := p.Position(ref, st)
+					cells := make([]int, 0, len(points))
+					for _, pt := range points {
+						cells = append(cells, pt.Y*m.Width+pt.X)
+					}
+
+					key := make([]int, len(cells))
+					copy(key, cells)
+					sort.Ints(key)
+					k := string(intsKey(key))
+					if _, ok := seen[k]; ok {
+						continue
+					}
+					seen[k] = struct{}{}
+
+					res = append(res, placement{piece: idx, state: st, cells: cells})
+				}
+			}
+		}
+	}
+	return res
+}
+
+func intsKey(v []int) []byte {
+	res := make([]byte, 0, len(v)*2)
+	for _, i := range v {
+		res = append(res, byte(i>>8), byte(i))
+	}
+	return res
+}
+
+// apply writes the placement of one of the pieces into the matrix.
+func (m *Matrix) apply(pieces []Piece, pl placement) {
+	name := pieces[pl.piece].Name()
+	for _, c := range pl.cells {
+		m.data[c] = name
+		m.own(c, pl.cells[0])
+	}
+	m.pieces[name] = pl.state
+}
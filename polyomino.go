@@ -0,0 +1,239 @@
+package psolver
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Polyomino is a piece made of any number of connected cells, with its states
+// generated from a single shape: the four rotations first, then the four
+// rotations of the reflected shape, with the duplicates removed.
+type Polyomino struct {
+	name   byte
+	states [][]Point
+}
+
+// rotations and reflections of a point around the origin.
+var orientationTransforms = []transform{
+	func(p Point) Point { return Point{p.X, p.Y} },
+	func(p Point) Point { return Point{-p.Y, p.X} },
+	func(p Point) Point { return Point{-p.X, -p.Y} },
+	func(p Point) Point { return Point{p.Y, -p.X} },
+	func(p Point) Point { return Point{-p.X, p.Y} },
+	func(p Point) Point { return Point{-p.Y, -p.X} },
+	func(p Point) Point { return Point{p.X, -p.Y} },
+	func(p Point) Point { return Point{p.Y, p.X} },
+}
+
+// normalize sorts the cells in scan order and moves them so the first one is
+// at the origin, the anchor the solver places on the first empty cell.
+func normalize(cells []Point) []Point {
+	res := make([]Point, len(cells))
+	copy(res, cells)
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Y != res[j].Y {
+			return res[i].Y < res[j].Y
+		}
+		return res[i].X < res[j].X
+	})
+	first := res[0]
+	for i := range res {
+		res[i] = Point{X: res[i].X - first.X, Y: res[i].Y - first.Y}
+	}
+	return res
+}
+
+// validShape checks that the cells are distinct and connected.
+func validShape(cells []Point) error {
+	if len(cells) == 0 {
+		return errors.New("empty shape")
+	}
+
+	set := map[Point]bool{}
+	for _, c := range cells {
+		if set[c] {
+			return fmt.Errorf("duplicate cell (%d, %d)", c.X, c.Y)
+		}
+		set[c] = true
+	}
+
+	seen := map[Point]bool{cells[0]: true}
+	queue := []Point{cells[0]}
+	for len(queue) > 0 {
+		c := queue[0]
+		queue = queue[1:]
+		for _, n := range []Point{{c.X - 1, c.Y}, {c.X + 1, c.Y}, {c.X, c.Y - 1}, {c.X, c.Y + 1}} {
+			if set[n] && !seen[n] {
+				seen[n] = true
+				queue = append(queue, n)
+			}
+		}
+	}
+	if len(seen) != len(cells) {
+		return errors.New("the cells are not connected")
+	}
+	return nil
+}
+
+// NewPolyomino creates a piece from the list of its cells, in any position.
+func NewPolyomino(name byte, cells []Point) (*Polyomino, error) {
+	if err := validShape(cells); err != nil {
+		return nil, fmt.Errorf("piece %q: %w", string(name), err)
+	}
+
+	p := &Polyomino{name: name}
+	seen := map[string]struct{}{}
+	for _, t := range orientationTransforms {
+		moved := make([]Point, len(cells))
+		for i, c := range cells {
+			moved[i] = t(c)
+		}
+		state := normalize(moved)
+		key := shapeKey(state)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		p.states = append(p.states, state)
+	}
+	return p, nil
+}
+
+func (p *Polyomino) Name() byte {
+	return p.name
+}
+
+func (p *Polyomino) States() int {
+	return len(p.states)
+}
+
+func (p *Polyomino) Position(ref Point, state int) ([]Point, error) {
+	if state < 0 || state >= len(p.states) {
+		return nil, ErrInvalidState
+	}
+
+	res := make([]Point, len(p.states[state]))
+	for i, c := range p.states[state] {
+		res[i] = Point{X: ref.X + c.X, Y: ref.Y + c.Y}
+	}
+	return res, nil
+}
+
+// NewShape creates a piece from a drawing of one of its orientations, the
+// rows are separated by new lines or "/", "." is an empty cell, spaces are
+// ignored and any other character is a cell of the piece. An empty row is a
+// row without cells:
+//
+//	NewShape('F', ". F F / F F . / . F .")
+func NewShape(name byte, shape string) (*Polyomino, error) {
+	var cells []Point
+	rows := strings.Split(strings.ReplaceAll(shape, "/", "\n"), "\n")
+	for y, row := range rows {
+		x := 0
+		for _, r := range row {
+			switch r {
+			case ' ', '\t', '\r':
+				continue
+			case '.':
+			default:
+				cells = append(cells, Point{X: x, Y: y})
+			}
+			x++
+		}
+	}
+	return NewPolyomino(name, cells)
+}
+
+// mustShape is NewShape for the built-in pieces.
+func mustShape(name byte, shape string) Piece {
+	p, err := NewShape(name, shape)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// Size returns the number of cells of the piece.
+func (p *Polyomino) Size() int {
+	return len(p.states[0])
+}
+
+// canonical returns the smallest normalised orientation of the shape, equal
+// for all the rotations and reflections of the same free polyomino.
+func canonical(cells []Point) string {
+	best := ""
+	for _, t := range orientationTransforms {
+		moved := make([]Point, len(cells))
+		for i, c := range cells {
+			moved[i] = t(c)
+		}
+		if key := shapeKey(moved); best == "" || key < best {
+			best = key
+		}
+	}
+	return best
+}
+
+// Polyominoes returns one shape of each free polyomino of the given size, the
+// rotations and reflections of a shape are counted once. There are 5
+// tetrominoes, 12 pentominoes and 35 hexominoes.
+func Polyominoes(size int) [][]Point {
+	if size < 1 {
+		return nil
+	}
+
+	shapes := [][]Point{{{0, 0}}}
+	for k := 1; k < size; k++ {
+		seen := map[string]struct{}{}
+		var next [][]Point
+		for _, s := range shapes {
+			set := map[Point]bool{}
+			for _, c := range s {
+				set[c] = true
+			}
+			for _, c := range s {
+				for _, n := range []Point{{c.X - 1, c.Y}, {c.X + 1, c.Y}, {c.X, c.Y - 1}, {c.X, c.Y + 1}} {
+					if set[n] {
+						continue
+					}
+					grown := append(append([]Point{}, s...), n)
+					key := canonical(grown)
+					if _, ok := seen[key]; ok {
+						continue
+					}
+					seen[key] = struct{}{}
+					next = append(next, normalize(grown))
+				}
+			}
+		}
+		shapes = next
+	}
+	return shapes
+}
+
+// NewTetrominoes returns the five free tetrominoes, named with lower case
+// letters so they can be mixed with the pentominoes: i, o, t, l and s.
+func NewTetrominoes() []Piece {
+	shapes := []struct {
+		name  byte
+		cells []Point
+	}{
+		{'i', []Point{{0, 0}, {1, 0}, {2, 0}, {3, 0}}},
+		{'o', []Point{{0, 0}, {1, 0}, {0, 1}, {1, 1}}},
+		{'t', []Point{{0, 0}, {1, 0}, {2, 0}, {1, 1}}},
+		{'l', []Point{{0, 0}, {0, 1}, {0, 2}, {1, 2}}},
+		{'s', []Point{{1, 0}, {2, 0}, {0, 1}, {1, 1}}},
+	}
+
+	res := make([]Piece, 0, len(shapes))
+	for _, s := range shapes {
+		p, err := NewPolyomino(s.name, s.cells)
+		if err != nil {
+			panic(err)
+		}
+		res = append(res, p)
+	}
+	return res
+}
@@ -0,0 +1,87 @@
+package psolver
+
+import (
+	"context"
+	"testing"
+)
+
+func TestPolyominoStates(t *testing.T) {
+	tests := []struct {
+		cells []Point
+		want  int
+	}{
+		{cells: []Point{{0, 0}}, want: 1},
+		{cells: []Point{{0, 0}, {1, 0}, {0, 1}, {1, 1}}, want: 1},
+		{cells: []Point{{0, 0}, {1, 0}, {2, 0}, {3, 0}}, want: 2},
+		{cells: []Point{{0, 0}, {1, 0}, {2, 0}, {1, 1}}, want: 4},
+		{cells: []Point{{0, 0}, {0, 1}, {0, 2}, {1, 2}}, want: 8},
+	}
+
+	for _, tt := range tests {
+		p, err := NewPolyomino('A', tt.cells)
+		if err != nil {
+			t.Fatalf("NewPolyomino failed: %v", err)
+		}
+		if p.States() != tt.want {
+			t.Errorf("Expected %d states for %v, got %d", tt.want, tt.cells, p.States())
+		}
+		for st := range p.States() {
+			points, err := p.Position(Point{X: 3, Y: 3}, st)
+			if err != nil {
+				t.Fatalf("Position failed: %v", err)
+			}
+			if points[0] != (Point{X: 3, Y: 3}) {
+				t.Errorf("State %d does not start at the reference point: %v", st, points)
+			}
+		}
+	}
+}
+
+func TestPolyominoInvalid(t *testing.T) {
+	for _, cells := range [][]Point{
+		nil,
+		{{0, 0}, {0, 0}},
+		{{0, 0}, {2, 0}},
+	} {
+		if _, err := NewPolyomino('A', cells); err == nil {
+			t.Errorf("Expected error for %v", cells)
+		}
+	}
+}
+
+func TestPolyominoes(t *testing.T) {
+	for size, want := range map[int]int{1: 1, 2: 1, 3: 2, 4: 5, 5: 12, 6: 35} {
+		if got := len(Polyominoes(size)); got != want {
+			t.Errorf("Expected %d polyominoes of size %d, got %d", want, size, got)
+		}
+	}
+}
+
+func TestSolvePolyominoes(t *testing.T) {
+	var pentominoes []Piece
+	for i, cells := range Polyominoes(5) {
+		p, err := NewPolyomino(byte('A'+i), cells)
+		if err != nil {
+			t.Fatalf("NewPolyomino failed: %v", err)
+		}
+		pentominoes = append(pentominoes, p)
+	}
+
+	res, err := Count(context.Background(), NewMatrix(20, 3), pentominoes)
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if res.Total != 8 {
+		t.Errorf("Expected 8 solutions, got %d", res.Total)
+	}
+
+	// A mixed set, the square tetromino with the 12 pentominoes on the 8x8 board
+	mixed := append(New12(), NewTetrominoes()[1])
+	res, err = CountWithOptions(context.Background(), NewMatrix(8, 8), mixed, SolveOptions{Engine: EngineAuto, Unique: true, Limit: 10})
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if res.Total != 10 {
+		t.Errorf("Expected 10 solutions, got %d", res.Total)
+	}
+}
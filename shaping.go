@@ -0,0 +1,111 @@
+package psolver
+
+import (
+	"slices"
+	"strings"
+)
+
+// arabicForm is the first presentation form of a letter, the isolated one,
+// followed by the final, and for the dual joining letters the initial and
+// the medial forms.
+type arabicForm struct {
+	isolated rune
+	dual     bool
+}
+
+// arabicForms are the presentation forms of the letters of the Persian
+// alphabet, the fonts draw the joined letters with them.
+var arabicForms = map[rune]arabicForm{
+	'آ': {0xFE81, false},
+	'ئ': {0xFE89, true},
+	'ا': {0xFE8D, false},
+	'ب': {0xFE8F, true},
+	'ت': {0xFE95, true},
+	'ث': {0xFE99, true},
+	'ج': {0xFE9D, true},
+	'ح': {0xFEA1, true},
+	'خ': {0xFEA5, true},
+	'د': {0xFEA9, false},
+	'ذ': {0xFEAB, false},
+	'ر': {0xFEAD, false},
+	'ز': {0xFEAF, false},
+	'س': {0xFEB1, true},
+	'ش': {0xFEB5, true},
+	'ص': {0xFEB9, true},
+	'ض': {0xFEBD, true},
+	'ط': {0xFEC1, true},
+	'ظ': {0xFEC5, true},
+	'ع': {0xFEC9, true},
+	'غ': {0xFECD, true},
+	'ف': {0xFED1, true},
+	'ق': {0xFED5, true},
+	'ل': {0xFEDD, true},
+	'م': {0xFEE1, true},
+	'ن': {0xFEE5, true},
+	'ه': {0xFEE9, true},
+	'و': {0xFEED, false},
+	'پ': {0xFB56, true},
+	'چ': {0xFB7A, true},
+	'ژ': {0xFB8A, false},
+	'ک': {0xFB8E, true},
+	'گ': {0xFB92, true},
+	'ی': {0xFBFC, true},
+}
+
+// shape replaces the Persian letters of the word with their joined forms.
+// The zero width non-joiner stops the joining and is removed.
+func shape(word []rune) []rune {
+	res := make([]rune, 0, len(word))
+	for i, r := range word {
+		f, ok := arabicForms[r]
+		if !ok {
+			if r != '‌' {
+				res = append(res, r)
+			}
+			continue
+		}
+
+		prev := i > 0 && arabicForms[word[i-1]].dual
+		next := false
+		if i+1 < len(word) {
+			_, next = arabicForms[word[i+1]]
+		}
+		next = next && f.dual
+		switch {
+		case prev && next:
+			res = append(res, f.isolated+3)
+		case prev:
+			res = append(res, f.isolated+1)
+		case next:
+			res = append(res, f.isolated+2)
+		default:
+			res = append(res, f.isolated)
+		}
+	}
+	return res
+}
+
+// visual returns the text in the order it is drawn from left to right, for
+// the faces that do not lay out the Persian script. The words are joined and
+// written from right to left, and in a right to left text the order of the
+// words is reversed too. The numbers keep their order.
+func visual(s string, rtl bool) string {
+	words := strings.Split(s, " ")
+	for i, w := range words {
+		runes := []rune(w)
+		persian := slices.ContainsFunc(runes, func(r rune) bool {
+			_, ok := arabicForms[r]
+			return ok
+		})
+		if !persian {
+			continue
+		}
+		runes = shape(runes)
+		slices.Reverse(runes)
+		words[i] = string(runes)
+	}
+	if rtl {
+		slices.Reverse(words)
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,213 @@
+package psolver
+
+import (
+	"bytes"
+	"maps"
+	"slices"
+	"sort"
+)
+
+// symmetry is a transformation of the board, it maps each cell index to the
+// index of its image.
+type symmetry []int
+
+// transform is the linear part of a symmetry, applied to the piece offsets.
+type transform func(p Point) Point
+
+// boardSymmetries returns the symmetries of the board shape other than the
+// identity, with their linear parts.
+func boardSymmetries(w, h int) ([]symmetry, []transform) {
+	type sym struct {
+		cell func(x, y int) (int, int)
+		lin  transform
+	}
+	all := []sym{
+		{func(x, y int) (int, int) { return w - 1 - x, h - 1 - y }, func(p Point) Point { return Point{-p.X, -p.Y} }},
+		{func(x, y int) (int, int) { return w - 1 - x, y }, func(p Point) Point { return Point{-p.X, p.Y} }},
+		{func(x, y int) (int, int) { return x, h - 1 - y }, func(p Point) Point { return Point{p.X, -p.Y} }},
+	}
+	if w == h {
+		all = append(all,
+			sym{func(x, y int) (int, int) { return w - 1 - y, x }, func(p Point) Point { return Point{-p.Y, p.X} }},
+			sym{func(x, y int) (int, int) { return y, w - 1 - x }, func(p Point) Point { return Point{p.Y, -p.X} }},
+			sym{func(x, y int) (int, int) { return y, x }, func(p Point) Point { return Point{p.Y, p.X} }},
+			sym{func(x, y int) (int, int) { return w - 1 - y, w - 1 - x }, func(p Point) Point { return Point{-p.Y, -p.X} }},
+		)
+	}
+
+	var syms []symmetry
+	var lins []transform
+	for _, s := range all {
+		g := make(symmetry, w*h)
+		for j := 0; j < h; j++ {
+			for i := 0; i < w; i++ {
+				x, y := s.cell(i, j)
+				g[j*w+i] = y*w + x
+			}
+		}
+		syms = append(syms, g)
+		lins = append(lins, s.lin)
+	}
+	return syms, lins
+}
+
+// shapeKey is the normalised form of a set of points, equal for the same shape
+// in the same orientation wherever it is placed.
+func shapeKey(points []Point) string {
+	minX, minY := points[0].X, points[0].Y
+	for _, p := range points {
+		minX = min(minX, p.X)
+		minY = min(minY, p.Y)
+	}
+	cells := make([]int, 0, len(points))
+	for _, p := range points {
+		cells = append(cells, (p.Y-minY)<<8|(p.X-minX))
+	}
+	sort.Ints(cells)
+	return string(intsKey(cells))
+}
+
+// orientations returns the shape keys of the valid states of the piece.
+func orientations(p Piece, t transform) map[string]struct{} {
+	res := map[string]struct{}{}
+	for st := range p.States() {
+		points, err := p.Position(Point{}, st)
+		if err != nil {
+			continue
+		}
+		if t != nil {
+			for i := range points {
+				points[i] = t(points[i])
+			}
+		}
+		res[shapeKey(points)] = struct{}{}
+	}
+	return res
+}
+
+// symmetryFilter drops the solutions that are a symmetric image of another one.
+// The search is pruned by keeping only the placements of one piece that are
+// not greater than their own images, the rest of the duplicates, where that
+// piece is fixed by a symmetry, are removed by comparing the whole boards.
+// Only a piece placed exactly once can be restricted, without one the piece
+// is -1 and all the duplicates are removed by comparing the boards.
+type symmetryFilter struct {
+	syms  []symmetry
+	name  byte
+	piece int
+}
+
+// newSymmetryFilter returns the filter for the symmetries that map both the
+// board and the set of pieces onto themselves, nil if there is none. least and
+// most are the bounds of the copies of each piece.
+func newSymmetryFilter(m *Matrix, pieces []Piece, least, most []int) *symmetryFilter {
+	if len(pieces) == 0 {
+		return nil
+	}
+
+	syms, lins := boardSymmetries(m.Width, m.Height)
+	f := &symmetryFilter{}
+	for i, g := range syms {
+		valid := true
+		for c := range m.data {
+			if m.data[c] != m.data[g[c]] {
+				valid = false
+				break
+			}
+		}
+		for _, p := range pieces {
+			if !valid {
+				break
+			}
+			valid = maps.Equal(orientations(p, nil), orientations(p, lins[i]))
+		}
+		if valid {
+			f.syms = append(f.syms, g)
+		}
+	}
+	if len(f.syms) == 0 {
+		return nil
+	}
+
+	// Restrict the piece with the fewest placements, usually the X.
+	count := make([]int, len(pieces))
+	for _, pl := range placements(m, pieces) {
+		count[pl.piece]++
+	}
+	f.piece = -1
+	for i := range count {
+		if least[i] != 1 || most[i] != 1 {
+			continue
+		}
+		if f.piece < 0 || count[i] < count[f.piece] {
+			f.piece = i
+		}
+	}
+	if f.piece >= 0 {
+		f.name = pieces[f.piece].Name()
+	}
+	return f
+}
+
+func sortedCells(cells []int, g symmetry) []int {
+	res := make([]int, len(cells))
+	for i, c := range cells {
+		res[i] = c
+		if g != nil {
+			res[i] = g[c]
+		}
+	}
+	sort.Ints(res)
+	return res
+}
+
+// allows reports whether the piece may cover the cells.
+func (f *symmetryFilter) allows(name byte, cells []int) bool {
+	if f.piece < 0 || name != f.name {
+		return true
+	}
+	own := sortedCells(cells, nil)
+	for _, g := range f.syms {
+		if slices.Compare(own, sortedCells(cells, g)) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// filter removes the placements that are not allowed.
+func (f *symmetryFilter) filter(pls []placement) []placement {
+	res := pls[:0:0]
+	for _, pl := range pls {
+		if pl.piece != f.piece || f.allows(f.name, pl.cells) {
+			res = append(res, pl)
+		}
+	}
+	return res
+}
+
+// canonical reports whether the solution is not greater than its images under
+// the symmetries that keep the restricted piece in place, the other images are
+// already pruned by allows.
+func (f *symmetryFilter) canonical(m *Matrix) bool {
+	var cells []int
+	for c := range m.data {
+		if f.piece >= 0 && m.data[c] == f.name {
+			cells = append(cells, c)
+		}
+	}
+
+	img := make([]byte, len(m.data))
+	for _, g := range f.syms {
+		if f.piece >= 0 && !slices.Equal(cells, sortedCells(cells, g)) {
+			continue
+		}
+		for c := range m.data {
+			img[g[c]] = m.data[c]
+		}
+		if bytes.Compare(m.data, img) > 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,280 @@
+package psolver
+
+import (
+	"encoding/json"
+	"fmt"
+	icolor "image/color"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Color is a color of a theme, written as "#RRGGBB" or "#RRGGBBAA" in JSON.
+// The empty string and "none" are transparent.
+type Color struct {
+	R, G, B, A uint8
+}
+
+// RGBA implements the color.Color interface.
+func (c Color) RGBA() (uint32, uint32, uint32, uint32) {
+	return icolor.NRGBA{R: c.R, G: c.G, B: c.B, A: c.A}.RGBA()
+}
+
+// hex returns the color for SVG, "none" when it is transparent.
+func (c Color) hex() string {
+	if c.A == 0 {
+		return "none"
+	}
+	return fmt.Sprintf("#%02X%02X%02X", c.R, c.G, c.B)
+}
+
+// faded returns the color at half of its opacity.
+func (c Color) faded() Color {
+	c.A /= 2
+	return c
+}
+
+func (c *Color) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	p, err := parseColor(s)
+	if err != nil {
+		return err
+	}
+	*c = p
+	return nil
+}
+
+func (c Color) MarshalJSON() ([]byte, error) {
+	if c.A == 0 {
+		return json.Marshal("none")
+	}
+	if c.A == 0xFF {
+		return json.Marshal(c.hex())
+	}
+	return json.Marshal(fmt.Sprintf("%s%02X", c.hex(), c.A))
+}
+
+// parseColor parses "#RRGGBB" and "#RRGGBBAA".
+func parseColor(s string) (Color, error) {
+	if s == "" || s == "none" {
+		return Color{}, nil
+	}
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) == 6 {
+		hex += "FF"
+	}
+	n, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil || len(hex) != 8 || !strings.HasPrefix(s, "#") {
+		return Color{}, fmt.Errorf("%q is invalid color", s)
+	}
+	return Color{R: uint8(n >> 24), G: uint8(n >> 16), B: uint8(n >> 8), A: uint8(n)}, nil
+}
+
+// mustColors parses the colors of the built-in themes.
+func mustColors(s ...string) []Color {
+	res := make([]Color, len(s))
+	for i := range s {
+		c, err := parseColor(s[i])
+		if err != nil {
+			panic(err)
+		}
+		res[i] = c
+	}
+	return res
+}
+
+// Theme is the colors of the exporters.
+type Theme struct {
+	Name string `json:"name"`
+	// Pieces are the colors of the pieces by their name.
+	Pieces map[string]Color `json:"pieces"`
+	// Fallback colors are given to the pieces without a color, like the
+	// tetrominoes or the one-sided pentominoes, in the order of their names.
+	Fallback []Color `json:"fallback"`
+	// Blocked is the color of the blocked cells.
+	Blocked Color `json:"blocked"`
+	// Background of the images, transparent by default. The uncovered cells
+	// with a label have this color too, or white when it is transparent.
+	Background Color `json:"background"`
+	// Border is the color of the outlines of the pieces.
+	Border Color `json:"border"`
+	// Text is the color of the labels and of the footer.
+	Text Color `json:"text"`
+}
+
+// newTheme creates a built-in theme, the piece colors are in the order of
+// New12.
+func newTheme(name string, pieces, fallback []string, blocked, background, border, text string) *Theme {
+	t := &Theme{
+		Name:     name,
+		Pieces:   map[string]Color{},
+		Fallback: mustColors(fallback...),
+	}
+	for i, c := range mustColors(pieces...) {
+		t.Pieces[string("FILNPTUVWXYZ"[i])] = c
+	}
+	colors := mustColors(blocked, background, border, text)
+	t.Blocked, t.Background, t.Border, t.Text = colors[0], colors[1], colors[2], colors[3]
+	return t
+}
+
+// themes are the built-in themes.
+var themes = map[string]func() *Theme{
+	"default":       DefaultTheme,
+	"high-contrast": HighContrastTheme,
+	"colorblind":    ColorblindTheme,
+	"grayscale":     GrayscaleTheme,
+}
+
+// DefaultTheme returns the colors the exporters use by default.
+func DefaultTheme() *Theme {
+	return newTheme("default",
+		[]string{
+			"#E6194B", // Red
+			"#3CB44B", // Green
+			"#FFE119", // Yellow
+			"#0082C8", // Blue
+			"#F58230", // Orange
+			"#911EB4", // Purple
+			"#46F0F0", // Cyan
+			"#F032E6", // Magenta
+			"#D2F53C", // Lime
+			"#FABED4", // Pink
+			"#008080", // Teal
+			"#DCBEFF", // Lavender
+		},
+		[]string{
+			"#800000", // Maroon
+			"#808000", // Olive
+			"#000075", // Navy
+			"#9A6324", // Brown
+			"#AAFFC3", // Mint
+			"#FFD8B1", // Apricot
+			"#FFFAC8", // Beige
+			"#A9A9A9", // Grey
+		},
+		"#000000", "", "#000000", "#000000")
+}
+
+// HighContrastTheme returns saturated colors on a white background.
+func HighContrastTheme() *Theme {
+	return newTheme("high-contrast",
+		[]string{
+			"#FF0000", "#00C000", "#FFFF00", "#0000FF", "#FF8000", "#8000FF",
+			"#00FFFF", "#FF00FF", "#80FF00", "#FF80C0", "#006000", "#A0A0FF",
+		},
+		[]string{"#800000", "#808000", "#000080", "#804000", "#80FFC0", "#FFC080"},
+		"#000000", "#FFFFFF", "#000000", "#000000")
+}
+
+// ColorblindTheme returns the colors of Paul Tol and Okabe and Ito, told apart
+// with the common kinds of color blindness.
+func ColorblindTheme() *Theme {
+	return newTheme("colorblind",
+		[]string{
+			"#CC6677", "#332288", "#DDCC77", "#117733", "#88CCEE", "#882255",
+			"#44AA99", "#999933", "#AA4499", "#E69F00", "#0072B2", "#F0E442",
+		},
+		[]string{"#77AADD", "#EE8866", "#EEDD88", "#FFAABB", "#99DDFF", "#44BB99", "#BBCC33", "#AAAA00"},
+		"#000000", "", "#000000", "#000000")
+}
+
+// GrayscaleTheme returns shades of gray, for printing.
+func GrayscaleTheme() *Theme {
+	return newTheme("grayscale",
+		[]string{
+			"#F0F0F0", "#505050", "#E0E0E0", "#707070", "#C0C0C0", "#909090",
+			"#D0D0D0", "#606060", "#B0B0B0", "#808080", "#A0A0A0", "#404040",
+		},
+		[]string{"#F8F8F8", "#E8E8E8", "#989898", "#585858", "#C8C8C8", "#787878"},
+		"#000000", "#FFFFFF", "#000000", "#000000")
+}
+
+// Themes returns the names of the built-in themes.
+func Themes() []string {
+	return slices.Sorted(maps.Keys(themes))
+}
+
+// ParseTheme returns the built-in theme with the name.
+func ParseTheme(name string) (*Theme, error) {
+	t, ok := themes[name]
+	if !ok {
+		return nil, fmt.Errorf("%q is invalid theme", name)
+	}
+	return t(), nil
+}
+
+// LoadTheme reads a theme from JSON, the colors missing in it are the ones of
+// DefaultTheme:
+//
+//	{
+//	  "name": "example",
+//	  "pieces": {"F": "#FF0000", "R": "#00FF00"},
+//	  "fallback": ["#808080"],
+//	  "blocked": "#000000",
+//	  "background": "#FFFFFF",
+//	  "border": "#000000",
+//	  "text": "#000000"
+//	}
+func LoadTheme(r io.Reader) (*Theme, error) {
+	t := DefaultTheme()
+	if err := json.NewDecoder(r).Decode(t); err != nil {
+		return nil, err
+	}
+	for name := range t.Pieces {
+		if len(name) != 1 {
+			return nil, fmt.Errorf("%q is invalid piece name", name)
+		}
+	}
+	return t, nil
+}
+
+// themeOrDefault returns t, or DefaultTheme when it is nil.
+func themeOrDefault(t *Theme) *Theme {
+	if t == nil {
+		return DefaultTheme()
+	}
+	return t
+}
+
+// colors returns the colors of the pieces and of the blocked cells on the
+// board. The pieces without a color get the fallback colors in the order of
+// their names, so the pieces of a board do not share a color.
+func (t *Theme) colors(m *Matrix) map[byte]Color {
+	res := map[byte]Color{}
+	var unknown []byte
+	for _, val := range m.data {
+		if _, ok := res[val]; ok || val == 0 || slices.Contains(unknown, val) {
+			continue
+		}
+		if val == 'O' {
+			res[val] = t.Blocked
+		} else if c, ok := t.Pieces[string(val)]; ok {
+			res[val] = c
+		} else {
+			unknown = append(unknown, val)
+		}
+	}
+
+	slices.Sort(unknown)
+	for i, val := range unknown {
+		res[val] = t.Blocked
+		if len(t.Fallback) > 0 {
+			res[val] = t.Fallback[i%len(t.Fallback)]
+		}
+	}
+	return res
+}
+
+// cell returns the color of the uncovered cells with a label.
+func (t *Theme) cell() Color {
+	if t.Background.A == 0 {
+		return Color{0xFF, 0xFF, 0xFF, 0xFF}
+	}
+	return t.Background
+}
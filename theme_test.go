@@ -0,0 +1,89 @@
+package psolver
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestThemes(t *testing.T) {
+	for _, name := range Themes() {
+		theme, err := ParseTheme(name)
+		if err != nil {
+			t.Fatalf("ParseTheme(%q) failed: %v", name, err)
+		}
+		for _, p := range New12() {
+			if _, ok := theme.Pieces[string(p.Name())]; !ok {
+				t.Errorf("%s: no color for %c", name, p.Name())
+			}
+		}
+		if len(theme.Fallback) < 6 {
+			t.Errorf("%s: expected fallback colors for the one-sided pieces", name)
+		}
+	}
+	if _, err := ParseTheme("nope"); err == nil {
+		t.Error("Expected error for an unknown theme")
+	}
+}
+
+func TestLoadTheme(t *testing.T) {
+	in := `{"name": "mine", "pieces": {"F": "#FF0000", "R": "#00FF0080"}, "background": "#FFFFFF"}`
+	theme, err := LoadTheme(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("LoadTheme failed: %v", err)
+	}
+	if theme.Pieces["F"] != (Color{0xFF, 0, 0, 0xFF}) || theme.Pieces["R"] != (Color{0, 0xFF, 0, 0x80}) {
+		t.Errorf("Unexpected piece colors %v", theme.Pieces)
+	}
+	// The missing colors are the default ones.
+	if theme.Pieces["I"] != DefaultTheme().Pieces["I"] || theme.Border != DefaultTheme().Border {
+		t.Error("Expected the default colors for the missing ones")
+	}
+
+	data, err := json.Marshal(theme.Pieces["R"])
+	if err != nil || string(data) != `"#00FF0080"` {
+		t.Errorf("Unexpected JSON %s, %v", data, err)
+	}
+
+	for _, in := range []string{
+		`{"pieces": {"F": "red"}}`,
+		`{"pieces": {"FF": "#FF0000"}}`,
+		`{"text": "#12345"}`,
+	} {
+		if _, err := LoadTheme(strings.NewReader(in)); err == nil {
+			t.Errorf("Expected error for %s", in)
+		}
+	}
+}
+
+func TestThemeFallback(t *testing.T) {
+	m := NewMatrix(4, 1)
+	copy(m.data, "FsiO")
+	theme := DefaultTheme()
+	colors := theme.colors(m)
+	if colors['F'] != theme.Pieces["F"] || colors['O'] != theme.Blocked {
+		t.Errorf("Unexpected colors %v", colors)
+	}
+	// The pieces without a color get the fallback ones in the order of their
+	// names.
+	if colors['i'] != theme.Fallback[0] || colors['s'] != theme.Fallback[1] {
+		t.Errorf("Unexpected fallback colors %v", colors)
+	}
+}
+
+func TestSVGExporterTheme(t *testing.T) {
+	m := NewMatrix(2, 1)
+	copy(m.data, "FO")
+	e := NewSVGExporter()
+	e.Theme = HighContrastTheme()
+	var buf bytes.Buffer
+	if err := e.Export(m, &buf); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	for _, want := range []string{`fill="#FFFFFF"/>`, `fill="#FF0000"`, `fill="#000000"`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("SVG should contain %s", want)
+		}
+	}
+}
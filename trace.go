@@ -0,0 +1,75 @@
+package psolver
+
+import (
+	"context"
+	"slices"
+)
+
+// Step is a piece placed on the board, or removed from it when the search
+// backtracks.
+type Step struct {
+	Placement
+	Removed bool
+}
+
+// Trace runs the search of SolveSingle on m and returns its steps, until the
+// first solution, the end of the search or limit steps, zero for no limit.
+// The pieces of a solution stay on the board after the last step. The steps
+// found so far are returned with ctx.Err() when ctx is cancelled. The search
+// keeps OneSided and Unique of opts, Engine and Limit are ignored. With
+// OneSided the states of the steps are the states of OneSided of the pieces.
+func Trace(ctx context.Context, m *Matrix, pieces []Piece, limit int, opts SolveOptions) ([]Step, error) {
+	opts.Limit = 1
+	s := newSearch(ctx, m, NewBag(pieces...), opts, nil)
+	defer s.cancel()
+
+	var steps []Step
+	s.trace = func(p Piece, pos Point, state int, removed bool) {
+		cells, _ := p.Position(pos, state)
+		steps = append(steps, Step{
+			Placement: Placement{Name: p.Name(), State: state, Cells: cells},
+			Removed:   removed,
+		})
+		if limit > 0 && len(steps) >= limit {
+			s.cancel()
+		}
+	}
+	s.backtrack(0, m.duplicate(), slices.Clone(s.most))
+	return steps, ctx.Err()
+}
+
+// SolutionSteps returns the placements of the pieces added to the board m in
+// the solution, in the order the search places them, from the first cell.
+func SolutionSteps(m, sol *Matrix) []Step {
+	pls := placementsOf(m, sol)
+	// The cells of a placement are in the order of the board.
+	slices.SortFunc(pls, func(a, b Placement) int {
+		return (a.Cells[0].Y*m.Width + a.Cells[0].X) - (b.Cells[0].Y*m.Width + b.Cells[0].X)
+	})
+
+	steps := make([]Step, len(pls))
+	for i := range pls {
+		steps[i] = Step{Placement: pls[i]}
+	}
+	return steps
+}
+
+// apply places or removes the piece of the step on the board.
+func (st Step) apply(m *Matrix) {
+	val := st.Name
+	if st.Removed {
+		val = 0
+		delete(m.pieces, st.Name)
+	} else {
+		m.pieces[st.Name] = st.State
+	}
+	first := st.Cells[0].Y*m.Width + st.Cells[0].X
+	for _, c := range st.Cells {
+		m.data[c.Y*m.Width+c.X] = val
+		if st.Removed {
+			m.own(c.Y*m.Width+c.X, -1)
+		} else {
+			m.own(c.Y*m.Width+c.X, first)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package psolver
+
+import (
+	"bytes"
+	"context"
+	"image/gif"
+	"testing"
+)
+
+func TestTrace(t *testing.T) {
+	m := NewMatrix(5, 3)
+	steps, err := Trace(context.Background(), m, smallSet(t, PieceP, PieceU, PieceV), 0, SolveOptions{})
+	if err != nil {
+		t.Fatalf("Trace failed: %v", err)
+	}
+
+	board := m.duplicate()
+	for _, st := range steps {
+		st.apply(board)
+	}
+	if len(board.Pieces()) != 3 || board.EmptyCells() != 0 {
+		t.Errorf("Expected a solution after the last step, got\n%s", board)
+	}
+	if m.String() != ".....\n.....\n.....\n" {
+		t.Errorf("The board is changed\n%s", m)
+	}
+
+	steps, err = Trace(context.Background(), m, smallSet(t, PieceP, PieceU, PieceV), 2, SolveOptions{})
+	if err != nil {
+		t.Fatalf("Trace failed: %v", err)
+	}
+	if len(steps) != 2 {
+		t.Errorf("Expected 2 steps, got %d", len(steps))
+	}
+}
+
+func TestSolutionSteps(t *testing.T) {
+	m := NewMatrix(5, 3)
+	sol := <-SolveContext(context.Background(), m, smallSet(t, PieceP, PieceU, PieceV), SolveOptions{Limit: 1})
+	if sol == nil {
+		t.Fatal("Expected a solution")
+	}
+
+	steps := SolutionSteps(m, sol)
+	if len(steps) != 3 {
+		t.Fatalf("Expected 3 steps, got %d", len(steps))
+	}
+	// The first step covers the top left cell.
+	if c := steps[0].Cells[0]; c.X != 0 || c.Y != 0 {
+		t.Errorf("Expected the first step on the first cell, got %v", c)
+	}
+	board := m.duplicate()
+	for _, st := range steps {
+		st.apply(board)
+	}
+	if board.String() != sol.String() {
+		t.Errorf("Expected\n%s\ngot\n%s", sol, board)
+	}
+}
+
+func TestAnimationExporter(t *testing.T) {
+	cal := NewGregorianCalendar()
+	if err := cal.SetDate(1, 5, 6, 2025); err != nil {
+		t.Fatalf("SetDate failed: %v", err)
+	}
+	sol := <-SolveContext(context.Background(), cal.Board(), New12(), SolveOptions{Engine: EngineAuto, Limit: 1})
+	if sol == nil {
+		t.Fatal("Expected a solution")
+	}
+	sol.Footer = cal.Footer
+
+	e := NewAnimationExporter()
+	var buf bytes.Buffer
+	if err := e.Export(sol, &buf); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	g, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatalf("Output should be a GIF file: %v", err)
+	}
+	// The empty board and one frame for each piece.
+	if len(g.Image) != 13 || g.Delay[12] != e.LastDelay {
+		t.Errorf("Expected 13 frames and the last one longer, got %d", len(g.Image))
+	}
+
+	e.APNG = true
+	buf.Reset()
+	if err := e.Export(sol, &buf); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	chunks, err := pngChunks(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Output should be a PNG file: %v", err)
+	}
+	n := map[string]int{}
+	for _, c := range chunks {
+		n[c.typ]++
+	}
+	if chunks[1].typ != "acTL" || n["fcTL"] != 13 || n["IDAT"] == 0 || n["fdAT"] < 12 {
+		t.Errorf("Unexpected APNG chunks %v", n)
+	}
+}
+
+func TestTraceOneSided(t *testing.T) {
+	allowed := map[byte]map[string]struct{}{}
+	for _, p := range New12() {
+		one := OneSided(p)
+		allowed[p.Name()] = map[string]struct{}{}
+		for st := range one.States() {
+			points, _ := one.Position(Point{}, st)
+			allowed[p.Name()][shapeKey(points)] = struct{}{}
+		}
+	}
+
+	steps, err := Trace(context.Background(), NewMatrix(10, 6), New12(), 500, SolveOptions{OneSided: true})
+	if err != nil {
+		t.Fatalf("Trace failed: %v", err)
+	}
+	for _, st := range steps {
+		if _, ok := allowed[st.Name][shapeKey(st.Cells)]; !ok {
+			t.Fatalf("Unexpected flipped %q at %v", st.Name, st.Cells)
+		}
+	}
+}
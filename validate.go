@@ -0,0 +1,63 @@
+package psolver
+
+import "fmt"
+
+// Validate checks that m is a solution for the pieces: there is no empty cell
+// and each piece is on the board exactly once, in one of its states. The cells
+// with "O" are blocked, any other letter must be the name of a piece.
+func Validate(m *Matrix, pieces []Piece) error {
+	return ValidateBag(NewMatrix(m.Width, m.Height), m, NewBag(pieces...))
+}
+
+// ValidateBoard is Validate for a solution of the board, it also checks that
+// the solution keeps the blocked cells and the pieces already placed on the
+// board.
+func ValidateBoard(board, m *Matrix, pieces []Piece) error {
+	return ValidateBag(board, m, NewBag(pieces...))
+}
+
+// ValidateBag is ValidateBoard for a bag of pieces, each piece is on the board
+// as many times as the bag allows, in the states of its copies.
+func ValidateBag(board, m *Matrix, bag Bag) error {
+	if len(m.data) != m.Width*m.Height {
+		return fmt.Errorf("the size %dx%d is invalid", m.Width, m.Height)
+	}
+	if board.Width != m.Width || board.Height != m.Height {
+		return fmt.Errorf("the size %dx%d is invalid, expected %dx%d", m.Width, m.Height, board.Width, board.Height)
+	}
+	for i, c := range board.data {
+		if c != 0 && m.data[i] != c {
+			return fmt.Errorf("cell (%d, %d) is %q, expected %q", i%m.Width, i/m.Width, string(m.data[i]), string(c))
+		}
+	}
+
+	pieces, least, most := bag.bounds()
+	names := map[byte]struct{}{'O': {}}
+	for _, p := range pieces {
+		names[p.Name()] = struct{}{}
+	}
+	for i, c := range m.data {
+		if c == 0 {
+			return fmt.Errorf("cell (%d, %d) is empty", i%m.Width, i/m.Width)
+		}
+		if _, ok := names[c]; !ok {
+			return fmt.Errorf("cell (%d, %d) is %q, not a piece", i%m.Width, i/m.Width, string(c))
+		}
+	}
+
+	// detect records the copies it finds, m keeps the placements of the solver.
+	d := m.duplicate()
+	for i, p := range pieces {
+		states, err := d.detect(p, most[i])
+		if err != nil {
+			return err
+		}
+		if len(states) < least[i] {
+			if len(states) == 0 {
+				return fmt.Errorf("%q is not on the board", string(p.Name()))
+			}
+			return fmt.Errorf("%q is on the board %d times, expected %d", string(p.Name()), len(states), least[i])
+		}
+	}
+	return nil
+}
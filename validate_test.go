@@ -0,0 +1,107 @@
+package psolver
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	pieces := smallSet(t, PieceP, PieceU, PieceV)
+	tests := []struct {
+		name  string
+		board string
+		valid bool
+	}{
+		{name: "solution", board: "UUVVV\nUPPPV\nUUPPV\n", valid: true},
+		{name: "blocked", board: "UUVVVO\nUPPPVO\nUUPPVO\n", valid: true},
+		{name: "gap", board: "UUVVV\nUPPPV\nUUP.V\n"},
+		{name: "missing", board: "UUOOO\nUOOOO\nUUOOO\n"},
+		{name: "shape", board: "UUVVV\nUPPPV\nUUPVP\n"},
+		{name: "twice", board: "UUVVVUU\nUPPPVUO\nUUPPVUU\n"},
+		{name: "unknown", board: "UUVVVX\nUPPPVX\nUUPPVX\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := parseMatrix(strings.NewReader(tt.board))
+			if err != nil {
+				t.Fatalf("parseMatrix failed: %v", err)
+			}
+			err = Validate(m, pieces)
+			if tt.valid && err != nil {
+				t.Errorf("Expected valid, got %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
+
+func TestValidateBoard(t *testing.T) {
+	pieces := smallSet(t, PieceP, PieceU, PieceV)
+	board, _ := parseMatrix(strings.NewReader("....O\n.....\n.....\n"))
+	sol, _ := parseMatrix(strings.NewReader("UUVVV\nUPPPV\nUUPPV\n"))
+	if err := ValidateBoard(board, sol, pieces); err == nil {
+		t.Error("Expected error for the covered blocked cell")
+	}
+	if err := ValidateBoard(NewMatrix(6, 3), sol, pieces); err == nil {
+		t.Error("Expected error for the size")
+	}
+	if err := ValidateBoard(NewMatrix(5, 3), sol, pieces); err != nil {
+		t.Errorf("Expected valid, got %v", err)
+	}
+}
+
+func TestValidateBag(t *testing.T) {
+	bag, err := ParseBag("L*2,I?")
+	if err != nil {
+		t.Fatalf("ParseBag failed: %v", err)
+	}
+	tests := []struct {
+		name  string
+		board string
+		valid bool
+	}{
+		{name: "copies", board: "LLLLL\nLLLLL\n", valid: true},
+		{name: "optional", board: "LLLLL\nLLLLL\nIIIII\n", valid: true},
+		{name: "one copy", board: "LLLLO\nLOOOO\n"},
+		{name: "three copies", board: "LLLLL\nLLLLL\nLLLLO\nLOOOO\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := parseMatrix(strings.NewReader(tt.board))
+			if err != nil {
+				t.Fatalf("parseMatrix failed: %v", err)
+			}
+			err = ValidateBag(NewMatrix(m.Width, m.Height), m, bag)
+			if tt.valid && err != nil {
+				t.Errorf("Expected valid, got %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
+
+func TestValidateKeepsPlacements(t *testing.T) {
+	bag, err := ParseBag("L*2")
+	if err != nil {
+		t.Fatalf("ParseBag failed: %v", err)
+	}
+	sol := <-SolveBag(context.Background(), NewMatrix(5, 2), bag, SolveOptions{Limit: 1})
+	if sol == nil {
+		t.Fatal("Expected a solution")
+	}
+	owner := slices.Clone(sol.owner)
+	if err := ValidateBag(NewMatrix(5, 2), sol, bag); err != nil {
+		t.Fatalf("Expected valid, got %v", err)
+	}
+	if !slices.Equal(owner, sol.owner) {
+		t.Errorf("The placements changed from %v to %v", owner, sol.owner)
+	}
+}